@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdownExtensions are the goldmark extensions that can be enabled by
+// name with -ext.
+var markdownExtensions = map[string]goldmark.Extender{
+	"table":         extension.Table,
+	"strikethrough": extension.Strikethrough,
+	"tasklist":      extension.TaskList,
+	"linkify":       extension.Linkify,
+	"footnote":      extension.Footnote,
+	"deflist":       extension.DefinitionList,
+	"typographer":   extension.Typographer,
+}
+
+// gfmExtensions are the extensions enabled by -gfm.
+var gfmExtensions = []string{"table", "strikethrough", "tasklist", "linkify"}
+
+// extensions are used for every conversion. They are set from the
+// command line flags.
+var extensions []goldmark.Extender
+
+// parseExtensions returns the extensions in the comma separated list
+// names, plus the GitHub Flavored Markdown ones if gfm is set.
+func parseExtensions(names string, gfm bool) ([]goldmark.Extender, error) {
+	var list []string
+	if gfm {
+		list = append(list, gfmExtensions...)
+	}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" && !slices.Contains(list, name) {
+			list = append(list, name)
+		}
+	}
+
+	var exts []goldmark.Extender
+	for _, name := range list {
+		ext, ok := markdownExtensions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown extension %q", name)
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
+
+func extensionNames() string {
+	names := make([]string, 0, len(markdownExtensions))
+	for name := range markdownExtensions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// policy is bluemonday's policy for user generated content, extended to
+// keep the markup of the goldmark extensions: table cell alignment, task
+// list checkboxes, footnote references and highlighted code.
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowStyles("text-align").MatchingEnum("left", "center", "right").OnElements("th", "td")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^footnote(s|-ref|-backref)$`)).OnElements("a", "div")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|endnotes|backlink)$`)).OnElements("a", "div")
+	allowHighlighting(p)
+	return p
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseExtensions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		names    string
+		gfm      bool
+		expected int
+		err      bool
+	}{
+		{"None", "", false, 0, false},
+		{"GFM", "", true, 4, false},
+		{"GFMAndFootnote", "footnote, Table", true, 5, false},
+		{"Unknown", "emoji", false, 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exts, err := parseExtensions(tc.names, tc.gfm)
+			if tc.err {
+				if err == nil {
+					t.Fatal("Expected error, got nil instead")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(exts) != tc.expected {
+				t.Errorf("Expected %d extensions, got %d instead", tc.expected, len(exts))
+			}
+		})
+	}
+}
+
+func TestParseContentExtensions(t *testing.T) {
+	exts, err := parseExtensions("footnote", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extensions = exts
+	t.Cleanup(func() { extensions = nil })
+
+	input := "| a | b |\n|:--|--:|\n| 1 | 2 |\n\n- [x] done\n- [ ] open\n\n" +
+		"~~gone~~ www.example.com\n\nNote[^1]\n\n[^1]: Foot\n"
+	result, err := parseContent([]byte(input), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		`<th style="text-align: left">a</th>`,
+		`<td style="text-align: right">2</td>`,
+		`<input checked="" disabled="" type="checkbox"> done`,
+		`<input disabled="" type="checkbox"> open`,
+		`<del>gone</del>`,
+		`<a href="http://www.example.com" rel="nofollow">`,
+		`<a href="#fn:1" class="footnote-ref" role="doc-noteref" rel="nofollow">1</a>`,
+		`<div class="footnotes" role="doc-endnotes">`,
+	} {
+		if !strings.Contains(string(result), s) {
+			t.Errorf("Expected %q in output, got:\n%s", s, result)
+		}
+	}
+
+	expected := `<input disabled=""><a href="#x" rel="nofollow">x</a>`
+	if got := policy.Sanitize(`<input type="text" disabled class="x"><a href="#x" class="x">x</a>`); got != expected {
+		t.Errorf("Expected %q, got %q instead", expected, got)
+	}
+}
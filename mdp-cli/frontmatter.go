@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/yuin/goldmark/ast"
+	"gopkg.in/yaml.v3"
+)
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// splitFrontMatter separates YAML front matter between --- lines or TOML
+// front matter between +++ lines at the top of input from the Markdown
+// that follows it. A block that doesn't decode to a mapping, such as a
+// thematic break followed by a setext heading, is ordinary Markdown.
+func splitFrontMatter(input []byte) (map[string]any, []byte) {
+	params := map[string]any{}
+	src := bytes.ReplaceAll(input, []byte("\r\n"), []byte("\n"))
+	var delim string
+	switch {
+	case bytes.HasPrefix(src, []byte("---\n")):
+		delim = "---"
+	case bytes.HasPrefix(src, []byte("+++\n")):
+		delim = "+++"
+	default:
+		return params, input
+	}
+
+	rest := src[len(delim)+1:]
+	var meta, body []byte
+	switch {
+	case bytes.HasPrefix(rest, []byte(delim+"\n")):
+		body = rest[len(delim)+1:]
+	case bytes.Equal(rest, []byte(delim)):
+	default:
+		end := bytes.Index(rest, []byte("\n"+delim+"\n"))
+		if end < 0 {
+			if !bytes.HasSuffix(rest, []byte("\n"+delim)) {
+				return params, input
+			}
+			end = len(rest) - len(delim) - 1
+		}
+		meta, body = rest[:end+1], rest[min(len(rest), end+len(delim)+2):]
+	}
+
+	var v any
+	var err error
+	if delim == "---" {
+		err = yaml.Unmarshal(meta, &v)
+	} else {
+		err = toml.Unmarshal(meta, &v)
+	}
+	if err != nil {
+		return params, input
+	}
+	switch m := v.(type) {
+	case nil:
+	case map[string]any:
+		params = m
+	default:
+		return params, input
+	}
+	return params, body
+}
+
+// setParams fills the title, author and date of c from the front matter
+// params, which are also exposed as they are.
+func (c *content) setParams(params map[string]any) {
+	c.Params = params
+	if v, ok := params["title"].(string); ok && v != "" {
+		c.Title = v
+	}
+	if v, ok := params["author"].(string); ok {
+		c.Author = v
+	}
+	switch v := params["date"].(type) {
+	case time.Time:
+		c.Date = v
+	case string:
+		for _, layout := range dateLayouts {
+			if d, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				c.Date = d
+				break
+			}
+		}
+	}
+}
+
+// firstHeading returns the text of the first level 1 heading in doc.
+func firstHeading(doc ast.Node, src []byte) string {
+	title := ""
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok || h.Level != 1 {
+			return ast.WalkContinue, nil
+		}
+		title = strings.TrimSpace(string(nodeText(h, src)))
+		return ast.WalkStop, nil
+	})
+	return title
+}
+
+func nodeText(n ast.Node, src []byte) []byte {
+	var buf bytes.Buffer
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			buf.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+			continue
+		}
+		buf.Write(nodeText(c, src))
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseContentFrontMatter(t *testing.T) {
+	tmpl := filepath.Join(t.TempDir(), "meta.html")
+	meta := `{{ .Title }}|{{ .Author }}|{{ .Date.Format "2006-01-02" }}|{{ index .Params "draft" }}`
+	if err := os.WriteFile(tmpl, []byte(meta), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"YAML", "---\ntitle: Release Notes\nauthor: Jane\ndate: 2026-03-04\ndraft: true\n---\n# Heading\n",
+			"Release Notes|Jane|2026-03-04|true"},
+		{"TOML", "+++\ntitle = \"Release Notes\"\nauthor = \"Jane\"\ndate = 2026-03-04\ndraft = false\n+++\n# Heading\n",
+			"Release Notes|Jane|2026-03-04|false"},
+		{"CRLF", "---\r\nauthor: Jane\r\ndate: \"2026-03-04\"\r\n---\r\n# Heading\r\n",
+			"Heading|Jane|2026-03-04|"},
+		{"HeadingTitle", "Intro\n\n# First *Heading*\n\n# Second\n",
+			"First Heading||0001-01-01|"},
+		{"DefaultTitle", "## Only a subheading\n",
+			"Markdown Preview Tool||0001-01-01|"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseContent([]byte(tc.input), tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result) != tc.expected {
+				t.Errorf("Expected %q, got %q instead", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	params, body := splitFrontMatter([]byte("---\ntags: [a, b]\n---\nText\n"))
+	if string(body) != "Text\n" {
+		t.Errorf("Expected body %q, got %q instead", "Text\n", body)
+	}
+	if tags, ok := params["tags"].([]any); !ok || len(tags) != 2 {
+		t.Errorf("Expected 2 tags, got %v instead", params["tags"])
+	}
+
+	for _, input := range []string{"---\n---\nText\n", "+++\n+++\nText\n"} {
+		params, body := splitFrontMatter([]byte(input))
+		if string(body) != "Text\n" || len(params) != 0 {
+			t.Errorf("Expected body %q and no params, got %q and %v instead", "Text\n", body, params)
+		}
+	}
+
+	// Blocks that are not front matter are left to the Markdown.
+	for _, input := range []string{
+		// A thematic break that is never closed.
+		"---\nText\n",
+		// A thematic break followed by a setext heading.
+		"---\nSome Heading\n---\n",
+		// The same with text that is not valid YAML.
+		"---\nNote: see: here\n---\n",
+		"---\ntitle: [\n---\n",
+	} {
+		params, body := splitFrontMatter([]byte(input))
+		if string(body) != input || len(params) != 0 {
+			t.Errorf("Expected body %q and no params, got %q and %v instead", input, body, params)
+		}
+	}
+}
+
+func TestSetParamsDate(t *testing.T) {
+	var c content
+	c.setParams(map[string]any{"date": "2026-03-04 10:30"})
+	expected := time.Date(2026, 3, 4, 10, 30, 0, 0, time.Local)
+	if !c.Date.Equal(expected) {
+		t.Errorf("Expected %v, got %v instead", expected, c.Date)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+)
+
+// highlightCSS is the style sheet for highlighting with CSS classes,
+// passed to the template as Style.
+var highlightCSS template.CSS
+
+// newHighlighting returns the extension highlighting fenced code blocks
+// with the chroma style theme. With classes, the code is marked up with
+// CSS classes and the style sheet defining them is returned; otherwise
+// the styles are inlined.
+func newHighlighting(theme string, classes bool) (goldmark.Extender, template.CSS, error) {
+	style, ok := styles.Registry[theme]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown theme %q", theme)
+	}
+	ext := highlighting.NewHighlighting(
+		highlighting.WithStyle(theme),
+		highlighting.WithFormatOptions(chromahtml.WithClasses(classes)),
+	)
+	if !classes {
+		return ext, "", nil
+	}
+	var css bytes.Buffer
+	if err := chromahtml.New(chromahtml.WithClasses(true)).WriteCSS(&css, style); err != nil {
+		return nil, "", err
+	}
+	return ext, template.CSS(css.String()), nil
+}
+
+// allowHighlighting lets the markup of highlighted code blocks through p:
+// chroma's single word classes, or the colors and font styles it inlines.
+func allowHighlighting(p *bluemonday.Policy) {
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-z]+$`)).OnElements("pre", "span")
+	p.AllowStyles("color", "background-color").OnElements("pre", "span")
+	p.AllowStyles("font-weight", "font-style", "text-decoration").OnElements("span")
+	p.AllowStyles("display").MatchingEnum("flex").OnElements("span")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func TestParseContentHighlight(t *testing.T) {
+	input := "# Code\n\n```go\nfunc main() {}\n```\n"
+	testCases := []struct {
+		name        string
+		classes     bool
+		contains    []string
+		notContains []string
+	}{
+		{"InlineStyles", false,
+			[]string{`<pre style="background-color: #fff">`, `<span style="color: #000; font-weight: bold">func</span>`},
+			[]string{"<style>", `class="`}},
+		{"CSSClasses", true,
+			[]string{`<pre class="chroma">`, `<span class="kd">func</span>`, "<style>\n", ".chroma .kd {"},
+			[]string{`style="`}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ext, css, err := newHighlighting("github", tc.classes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			extensions, highlightCSS = []goldmark.Extender{ext}, css
+			t.Cleanup(func() { extensions, highlightCSS = nil, "" })
+
+			result, err := parseContent([]byte(input), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(string(result), s) {
+					t.Errorf("Expected %q in output, got:\n%s", s, result)
+				}
+			}
+			for _, s := range tc.notContains {
+				if strings.Contains(string(result), s) {
+					t.Errorf("Expected no %q in output, got:\n%s", s, result)
+				}
+			}
+		})
+	}
+}
+
+func TestNewHighlightingUnknownTheme(t *testing.T) {
+	if _, _, err := newHighlighting("no-such-theme", false); err == nil {
+		t.Error("Expected error for unknown theme, got nil")
+	}
+}
@@ -11,8 +11,9 @@ import (
 	"runtime"
 	"time"
 
-	"github.com/microcosm-cc/bluemonday"
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
 )
 
 const (
@@ -20,7 +21,9 @@ const (
  <html>
  <head>
  <meta http-equiv="content-type" content="text/html; charset=utf-8">
- <title>{{ .Title }}</title>
+ <title>{{ .Title }}</title>{{ with .Style }}
+ <style>
+{{ . }} </style>{{ end }}
  </head>
  <body>
  {{ .Body }}
@@ -29,33 +32,67 @@ const (
  `
 )
 
+const defaultTitle = "Markdown Preview Tool"
+
+// content is passed to the template. Title, Author and Date come from
+// the front matter, with the title falling back to the first level 1
+// heading; Params holds every front matter field. Style is the style
+// sheet for highlighted code, if any.
 type content struct {
-	Title string
-	Body  template.HTML
+	Title  string
+	Author string
+	Date   time.Time
+	Params map[string]any
+	Style  template.CSS
+	Body   template.HTML
 }
 
 func parseContent(input []byte, tFname string) ([]byte, error) {
-	var markdownBuf bytes.Buffer
-	if err := goldmark.Convert(input, &markdownBuf); err != nil {
-		panic(fmt.Sprintf("failed to convert markdown: %v", err))
+	c := newContent(input, nil)
+	t, err := loadTemplate(tFname)
+	if err != nil {
+		return nil, err
 	}
+	return c.execute(t)
+}
 
-	body := bluemonday.UGCPolicy().Sanitize(markdownBuf.String())
+// newContent converts the Markdown input, calling transform, if not nil,
+// on the parsed document before it is rendered.
+func newContent(input []byte, transform func(doc ast.Node)) content {
+	params, src := splitFrontMatter(input)
 
-	t, err := template.New("mdp").Parse(defaultTemplate)
-	if err != nil {
-		return nil, err
+	md := goldmark.New(goldmark.WithExtensions(extensions...))
+	doc := md.Parser().Parse(text.NewReader(src))
+	if transform != nil {
+		transform(doc)
 	}
-	if tFname != "" {
-		t, err = template.ParseFiles(tFname)
-		if err != nil {
-			return nil, err
-		}
+	var markdownBuf bytes.Buffer
+	if err := md.Renderer().Render(&markdownBuf, src, doc); err != nil {
+		panic(fmt.Sprintf("failed to convert markdown: %v", err))
 	}
+
+	body := policy.Sanitize(markdownBuf.String())
+
 	c := content{
-		Title: "Markdown Preview Tool",
+		Title: firstHeading(doc, src),
+		Style: highlightCSS,
 		Body:  template.HTML(body),
 	}
+	if c.Title == "" {
+		c.Title = defaultTitle
+	}
+	c.setParams(params)
+	return c
+}
+
+func loadTemplate(tFname string) (*template.Template, error) {
+	if tFname != "" {
+		return template.ParseFiles(tFname)
+	}
+	return template.New("mdp").Parse(defaultTemplate)
+}
+
+func (c content) execute(t *template.Template) ([]byte, error) {
 	// Create a buffer of bytes to write to file
 	var buffer bytes.Buffer
 	// Execute the template with the content type
@@ -63,7 +100,6 @@ func parseContent(input []byte, tFname string) ([]byte, error) {
 		return nil, err
 	}
 	return buffer.Bytes(), nil
-
 }
 
 func saveHTML(fileName string, data []byte) error {
@@ -98,6 +134,18 @@ func run(fileName string, tFname string, out io.Writer, skipPreview bool) error
 	return preview(outName)
 }
 
+func runSite(dir, outDir, tFname string, out io.Writer, skipPreview bool) error {
+	indexName, err := buildSite(dir, outDir, tFname)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, indexName)
+	if skipPreview {
+		return nil
+	}
+	return preview(indexName)
+}
+
 func preview(fname string) error {
 	cName := ""
 	cParams := []string{}
@@ -125,11 +173,48 @@ func main() {
 	fileName := flag.String("file", "", "Markdown file to preivew")
 	skipPreview := flag.Bool("s", false, "Skip auto-preview")
 	tFname := flag.String("t", "", "Alternate template name")
+	serveFlag := flag.Bool("serve", false, "Serve a live-reloading preview instead of writing an HTML file")
+	addr := flag.String("addr", "localhost:8080", "Address for -serve to listen on")
+	dir := flag.String("dir", "", "Directory of Markdown files to convert into a static site")
+	outDir := flag.String("out", "site", "Output directory for -dir")
+	gfm := flag.Bool("gfm", false, "Enable GitHub Flavored Markdown: tables, strikethrough, task lists and autolinks")
+	exts := flag.String("ext", "", "Comma separated goldmark extensions to enable: "+extensionNames())
+	highlight := flag.Bool("highlight", false, "Highlight the syntax of fenced code blocks")
+	theme := flag.String("theme", "github", "Highlighting theme, one of the chroma styles")
+	cssClasses := flag.Bool("css-classes", false, "Highlight with CSS classes and a style sheet instead of inline styles")
 	flag.Parse()
+	var err error
+	if extensions, err = parseExtensions(*exts, *gfm); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *highlight {
+		ext, css, err := newHighlighting(*theme, *cssClasses)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		extensions = append(extensions, ext)
+		highlightCSS = css
+	}
+	if *dir != "" {
+		if err := runSite(*dir, *outDir, *tFname, os.Stdout, *skipPreview); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	if *fileName == "" {
 		flag.Usage()
 		os.Exit(1)
 	}
+	if *serveFlag {
+		if err := serve(*fileName, *tFname, *addr, os.Stdout, *skipPreview); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	if err := run(*fileName, *tFname, os.Stdout, *skipPreview); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const reloadScript = `<script>new EventSource("/events").onmessage = function() { location.reload(); };</script>
+`
+
+// previewServer renders fileName on each request and tells connected
+// browsers to reload when it or the template changes. Other paths are
+// served from the Markdown file's directory so relative images work.
+type previewServer struct {
+	fileName string
+	tFname   string
+	mux      *http.ServeMux
+
+	mu      sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+func newPreviewServer(fileName, tFname string) *previewServer {
+	s := &previewServer{
+		fileName: fileName,
+		tFname:   tFname,
+		mux:      http.NewServeMux(),
+		clients:  map[chan struct{}]bool{},
+	}
+	s.mux.HandleFunc("GET /{$}", s.page)
+	s.mux.HandleFunc("GET /events", s.events)
+	s.mux.Handle("GET /", http.FileServer(http.Dir(filepath.Dir(fileName))))
+	return s
+}
+
+func (s *previewServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *previewServer) page(w http.ResponseWriter, r *http.Request) {
+	input, err := os.ReadFile(s.fileName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	htmlData, err := parseContent(input, s.tFname)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	page := string(htmlData)
+	if i := strings.LastIndex(page, "</body>"); i >= 0 {
+		page = page[:i] + reloadScript + page[i:]
+	} else {
+		page += reloadScript
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	io.WriteString(w, page)
+}
+
+// events streams a reload event to the browser for every change.
+func (s *previewServer) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[ch] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			if _, err := io.WriteString(w, "data: reload\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *previewServer) broadcast() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// version identifies the current state of the source and template files.
+func (s *previewServer) version() string {
+	v := ""
+	for _, name := range []string{s.fileName, s.tFname} {
+		if name == "" {
+			continue
+		}
+		if info, err := os.Stat(name); err == nil {
+			v += fmt.Sprintf("%d %d;", info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	return v
+}
+
+// watch polls the source files every interval and broadcasts a reload
+// when they change, until ctx is done.
+func (s *previewServer) watch(ctx context.Context, interval time.Duration) {
+	last := s.version()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if v := s.version(); v != last {
+				last = v
+				s.broadcast()
+			}
+		}
+	}
+}
+
+func serve(fileName, tFname, addr string, out io.Writer, skipPreview bool) error {
+	if _, err := os.Stat(fileName); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := newPreviewServer(fileName, tFname)
+	go s.watch(ctx, 250*time.Millisecond)
+	srv := &http.Server{
+		Handler:     s,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	url := "http://" + ln.Addr().String()
+	fmt.Fprintf(out, "Serving preview of %s on %s\n", fileName, url)
+	if !skipPreview {
+		go func() {
+			if err := preview(url); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}()
+	}
+	if err := srv.Serve(ln); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
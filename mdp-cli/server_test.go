@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreviewServer(t *testing.T) {
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(t.TempDir(), "test.md")
+	if err := os.WriteFile(fileName, input, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(fileName), "image.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newPreviewServer(fileName, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watch(ctx, 10*time.Millisecond)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	get := func(path string) string {
+		t.Helper()
+		resp, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d instead", http.StatusOK, resp.StatusCode)
+		}
+		return string(body)
+	}
+
+	page := get("/")
+	for _, s := range []string{"<h1>Test Markdown File</h1>", reloadScript + "</body>"} {
+		if !strings.Contains(page, s) {
+			t.Errorf("Expected page to contain %q, got %q", s, page)
+		}
+	}
+	if asset := get("/image.png"); asset != "png" {
+		t.Errorf("Expected asset to be served, got %q", asset)
+	}
+
+	resp, err := http.Get(ts.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected event stream, got %q", ct)
+	}
+
+	if err := os.WriteFile(fileName, []byte("# Changed title\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan string)
+	go func() {
+		line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		events <- line
+	}()
+	select {
+	case line := <-events:
+		if line != "data: reload\n" {
+			t.Errorf("Expected reload event, got %q", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for reload event")
+	}
+
+	if page := get("/"); !strings.Contains(page, "<h1>Changed title</h1>") {
+		t.Errorf("Expected page to be rendered again, got %q", page)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+const indexTemplate = `<ul>
+{{ range . }}<li><a href="{{ .Path }}">{{ .Title }}</a></li>
+{{ end }}</ul>
+`
+
+// sitePage is a converted page listed on the generated index.
+type sitePage struct {
+	Path  string
+	Title string
+}
+
+// buildSite converts every Markdown file under srcDir into an HTML page in
+// outDir with the template tFname, and copies all other files along.
+// Hidden files and directories are skipped, as is outDir when it is
+// inside srcDir. Unless srcDir has its own index.md, an index.html
+// linking to every page is generated. It returns the index page's path.
+func buildSite(srcDir, outDir, tFname string) (string, error) {
+	t, err := loadTemplate(tFname)
+	if err != nil {
+		return "", err
+	}
+	absOut, err := filepath.Abs(outDir)
+	if err != nil {
+		return "", err
+	}
+
+	var pages []sitePage
+	err = filepath.WalkDir(srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+		if rel != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if abs, err := filepath.Abs(p); err == nil && abs == absOut {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if !isMarkdown(p) {
+			return copyFile(p, filepath.Join(outDir, rel))
+		}
+		input, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		c := newContent(input, rewriteLinks)
+		htmlData, err := c.execute(t)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		rel = htmlName(rel)
+		pages = append(pages, sitePage{Path: filepath.ToSlash(rel), Title: c.Title})
+		return writeSiteFile(filepath.Join(outDir, rel), htmlData)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	indexName := filepath.Join(outDir, "index.html")
+	if slices.ContainsFunc(pages, func(p sitePage) bool { return p.Path == "index.html" }) {
+		return indexName, nil
+	}
+	slices.SortFunc(pages, func(a, b sitePage) int { return strings.Compare(a.Path, b.Path) })
+	var body bytes.Buffer
+	if err := template.Must(template.New("index").Parse(indexTemplate)).Execute(&body, pages); err != nil {
+		return "", err
+	}
+	title := filepath.Base(srcDir)
+	if abs, err := filepath.Abs(srcDir); err == nil {
+		title = filepath.Base(abs)
+	}
+	c := content{Title: title, Body: template.HTML(body.String())}
+	htmlData, err := c.execute(t)
+	if err != nil {
+		return "", err
+	}
+	return indexName, writeSiteFile(indexName, htmlData)
+}
+
+func isMarkdown(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".md")
+}
+
+func htmlName(name string) string {
+	return name[:len(name)-len(filepath.Ext(name))] + ".html"
+}
+
+// rewriteLinks points relative links to Markdown files at the HTML pages
+// they are converted to, keeping any query or fragment.
+func rewriteLinks(doc ast.Node) {
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		link, ok := n.(*ast.Link)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		dest := string(link.Destination)
+		i := strings.IndexAny(dest, "?#")
+		if i < 0 {
+			i = len(dest)
+		}
+		p := dest[:i]
+		if p == "" || strings.HasPrefix(p, "/") || strings.Contains(p, ":") || !isMarkdown(p) {
+			return ast.WalkContinue, nil
+		}
+		link.Destination = []byte(htmlName(p) + dest[i:])
+		return ast.WalkContinue, nil
+	})
+}
+
+func writeSiteFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	return saveHTML(name, data)
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
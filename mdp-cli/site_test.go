@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunSite(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"guide.md":        "# Guide\n\n[Setup](sub/setup.md#install) [Ext](https://example.com/x.md) [Img](logo.png)\n",
+		"sub/setup.md":    "---\ntitle: Setup\n---\nBack to the [guide](../guide.md?x=1).\n",
+		"logo.png":        "png",
+		".hidden/skip.md": "# Skip\n",
+	}
+	for name, data := range files {
+		name = filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := filepath.Join(src, "site")
+
+	// Build twice to check the output directory is not converted itself.
+	for range 2 {
+		var mockStdOut bytes.Buffer
+		if err := runSite(src, out, "", &mockStdOut, true); err != nil {
+			t.Fatal(err)
+		}
+		if expected := filepath.Join(out, "index.html"); mockStdOut.String() != expected {
+			t.Errorf("Expected %q, got %q instead", expected, mockStdOut.String())
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		contains []string
+	}{
+		{"guide.html", []string{"<title>Guide</title>", `href="sub/setup.html#install"`, `href="https://example.com/x.md"`, `href="logo.png"`}},
+		{"sub/setup.html", []string{"<title>Setup</title>", `href="../guide.html?x=1"`}},
+		{"index.html", []string{`<a href="guide.html">Guide</a>`, `<a href="sub/setup.html">Setup</a>`}},
+		{"logo.png", []string{"png"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(out, tc.name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tc.contains {
+				if !strings.Contains(string(data), s) {
+					t.Errorf("Expected %q in output, got:\n%s", s, data)
+				}
+			}
+		})
+	}
+
+	for _, name := range []string{".hidden", "site", "guide.md"} {
+		if _, err := os.Stat(filepath.Join(out, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s not to be in the site, got %v", name, err)
+		}
+	}
+}
+
+func TestRunSiteOwnIndex(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "index.md"), []byte("# Home\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "site")
+	if err := runSite(src, out, "", &bytes.Buffer{}, true); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<h1>Home</h1>") {
+		t.Errorf("Expected index.md to be the index page, got:\n%s", data)
+	}
+}
@@ -0,0 +1,57 @@
+package todo
+
+import "time"
+
+// ArchiveStore returns the store holding the items archived from s. It is
+// of the same kind as s, in a file named after s's path with an .archive
+// suffix.
+func ArchiveStore(s Store) Store {
+	return storeAt(s, s.Path()+".archive")
+}
+
+// Archive moves the completed items of s that were completed before
+// cutoff to its archive store and returns how many were moved. A zero
+// cutoff archives every completed item. Use History.Archive to be able
+// to undo it.
+func Archive(s Store, cutoff time.Time) (int, error) {
+	return NewHistory(s, 0).Archive(cutoff)
+}
+
+// archive removes and returns the items completed before cutoff. Items
+// are only archived along with all their subtasks, so no item left in l
+// loses its parent; links from the archived items are kept.
+func (l *List) archive(cutoff time.Time) List {
+	var archivable func(t item, seen map[string]bool) bool
+	archivable = func(t item, seen map[string]bool) bool {
+		if !t.Done || !cutoff.IsZero() && !t.CompletedAt.Before(cutoff) {
+			return false
+		}
+		if seen[t.ID] {
+			return true
+		}
+		seen[t.ID] = true
+		for _, c := range *l {
+			if c.Parent == t.ID && !archivable(c, seen) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var archived, kept List
+	for _, t := range *l {
+		if archivable(t, map[string]bool{}) {
+			archived = append(archived, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	*l = kept
+	if *l == nil {
+		*l = List{}
+	}
+	for _, t := range archived {
+		l.unlink(t.ID)
+	}
+	return archived
+}
@@ -0,0 +1,191 @@
+package todo_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestArchive(t *testing.T) {
+	testCases := []struct {
+		name   string
+		open   func(path string) todo.Store
+		suffix string
+	}{
+		{"json", func(p string) todo.Store { return todo.NewJSONStore(p) }, ".json"},
+		{"bolt", func(p string) todo.Store { return todo.NewBoltStore(p) }, ".db"},
+		{"todotxt", func(p string) todo.Store { return todo.NewTodoTxtStore(p) }, ".txt"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.open(filepath.Join(t.TempDir(), "todo"+tc.suffix))
+			err := todo.Modify(s, func(l *todo.List) error {
+				l.Add("old task")
+				l.Add("open task")
+				l.Add("new task")
+				if err := l.AddDependency(2, 1); err != nil {
+					return err
+				}
+				if err := l.Complete(1); err != nil {
+					return err
+				}
+				return l.Complete(3)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			n, err := todo.Archive(s, time.Now().AddDate(0, 0, -1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 0 {
+				t.Errorf("Expected nothing archived before the cutoff, got %d instead", n)
+			}
+
+			n, err = todo.Archive(s, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != 2 {
+				t.Errorf("Expected 2 tasks archived, got %d instead", n)
+			}
+
+			l := loadList(t, s)
+			if len(l) != 1 || l[0].Task != "open task" {
+				t.Fatalf("Expected only the open task to remain, got %v instead", l)
+			}
+			if len(l[0].BlockedBy) != 0 {
+				t.Errorf("Expected archived blocker to be unlinked, got %v", l[0].BlockedBy)
+			}
+
+			a := loadList(t, todo.ArchiveStore(s))
+			if len(a) != 2 || a[0].Task != "old task" || a[1].Task != "new task" || !a[0].Done {
+				t.Errorf("Expected completed tasks in the archive, got %v instead", a)
+			}
+
+			if n, err := todo.Archive(s, time.Time{}); err != nil || n != 0 {
+				t.Errorf("Expected nothing left to archive, got %d, %v instead", n, err)
+			}
+		})
+	}
+}
+
+func TestArchiveParentCycle(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	if err := todo.Modify(s, func(l *todo.List) error {
+		*l = newParentCycle(t)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := todo.Archive(s, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Expected both items of the cycle archived, got %d instead", n)
+	}
+}
+
+func TestHistoryArchive(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+	err := h.Modify("add", func(l *todo.List) error {
+		for _, task := range []string{"release", "write notes", "tag build", "water plants"} {
+			l.Add(task)
+		}
+		for _, i := range []int{2, 3} {
+			if err := l.SetParent(i, 1); err != nil {
+				return err
+			}
+		}
+		if err := l.Complete(2); err != nil {
+			return err
+		}
+		if err := l.ForceComplete(1); err != nil {
+			return err
+		}
+		return l.Complete(4)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, archiveBefore := loadList(t, s), loadList(t, todo.ArchiveStore(s))
+
+	n, err := h.Archive(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 tasks archived, got %d instead", n)
+	}
+	l := loadList(t, s)
+	if len(l) != 2 || l[0].Task != "release" || l[1].Task != "tag build" || l[1].Parent != l[0].ID {
+		t.Errorf("Expected the parent with its open subtask to remain, got %v instead", l)
+	}
+	a := loadList(t, todo.ArchiveStore(s))
+	if len(a) != 2 || a[0].Task != "write notes" || a[0].Parent != l[0].ID {
+		t.Errorf("Expected archived subtask to keep its parent, got %v instead", a)
+	}
+
+	if op, err := h.Undo(); err != nil || op != "archive" {
+		t.Fatalf("Expected to undo archive, got %q, %v instead", op, err)
+	}
+	if l := loadList(t, s); !reflect.DeepEqual(l, before) {
+		t.Errorf("Expected %v after undo, got %v instead", before, l)
+	}
+	if a := loadList(t, todo.ArchiveStore(s)); !reflect.DeepEqual(a, archiveBefore) {
+		t.Errorf("Expected archive %v after undo, got %v instead", archiveBefore, a)
+	}
+
+	if _, err := h.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if a := loadList(t, todo.ArchiveStore(s)); len(a) != 2 {
+		t.Errorf("Expected 2 archived tasks after redo, got %v instead", a)
+	}
+}
+
+func TestHistoryArchiveAfter(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+	err := h.Modify("add", func(l *todo.List) error {
+		l.Add("old task")
+		return l.Complete(1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := loadList(t, s); len(l) != 1 {
+		t.Fatalf("Expected nothing archived without ArchiveAfter, got %v", l)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	h.ArchiveAfter = 10 * time.Millisecond
+	err = h.Modify("add", func(l *todo.List) error {
+		l.Add("new task")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := loadList(t, s); len(l) != 1 || l[0].Task != "new task" {
+		t.Errorf("Expected the old task to be archived, got %v instead", l)
+	}
+
+	if op, err := h.Undo(); err != nil || op != "add" {
+		t.Fatalf("Expected to undo add, got %q, %v instead", op, err)
+	}
+	if l := loadList(t, s); len(l) != 1 || l[0].Task != "old task" {
+		t.Errorf("Expected the add and the archiving to be undone, got %v instead", l)
+	}
+	if a := loadList(t, todo.ArchiveStore(s)); len(a) != 0 {
+		t.Errorf("Expected empty archive, got %v instead", a)
+	}
+}
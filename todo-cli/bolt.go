@@ -0,0 +1,97 @@
+package todo
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var itemsBucket = []byte("items")
+
+// BoltStore keeps one item per key in a bbolt database, keyed by the
+// item's position so iteration preserves the list order. With a
+// Passphrase set each value is encrypted on its own.
+type BoltStore struct {
+	path string
+}
+
+func NewBoltStore(path string) *BoltStore {
+	return &BoltStore{path: path}
+}
+
+func (s *BoltStore) open() (*bolt.DB, error) {
+	return bolt.Open(s.path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+}
+
+func (s *BoltStore) Load(l *List) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	*l = (*l)[:0]
+	err = db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(itemsBucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			v, err := decrypt(v)
+			if err != nil {
+				return err
+			}
+			var t item
+			if err := json.Unmarshal(v, &t); err != nil {
+				return err
+			}
+			*l = append(*l, t)
+			return nil
+		})
+	})
+	return err
+}
+
+func (s *BoltStore) Save(l *List) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(itemsBucket) != nil {
+			if err := tx.DeleteBucket(itemsBucket); err != nil {
+				return err
+			}
+		}
+		b, err := tx.CreateBucket(itemsBucket)
+		if err != nil {
+			return err
+		}
+		for i, t := range *l {
+			v, err := json.Marshal(t)
+			if err != nil {
+				return err
+			}
+			if v, err = encrypt(v); err != nil {
+				return err
+			}
+			k := binary.BigEndian.AppendUint64(nil, uint64(i))
+			if err := b.Put(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) Lock() (func() error, error) {
+	return Lock(s.path)
+}
+
+func (s *BoltStore) Path() string {
+	return s.path
+}
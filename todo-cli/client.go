@@ -0,0 +1,152 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client talks to a server started with NewHandler.
+type Client struct {
+	// Token, when set, is sent to servers wrapped with RequireToken.
+	Token string
+
+	url  string
+	http *http.Client
+}
+
+// remoteErrors are recognized in server error messages so callers can
+// match them with errors.Is as they would for a local list.
+var remoteErrors = []error{
+	ErrNotFound, ErrAmbiguousRef, ErrInvalidFilter, ErrInvalidSortKey,
+	ErrInvalidPriority, ErrInvalidDate, ErrInvalidRecurrence, ErrInvalidRequest,
+	ErrEmptyTask, ErrOpenSubtasks, ErrBlocked, ErrCycle, ErrUnauthorized,
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		url:  strings.TrimSuffix(baseURL, "/"),
+		http: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *Client) do(method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		js, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(js)
+	}
+	req, err := http.NewRequest(method, c.url+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var e errorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+			e.Error = resp.Status
+		}
+		err := fmt.Errorf("%w: %s", ErrRemote, e.Error)
+		for _, known := range remoteErrors {
+			if strings.HasPrefix(e.Error, known.Error()) {
+				return fmt.Errorf("%w: %w", known, err)
+			}
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %w", ErrNotFound, err)
+		}
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) List(filter, sortBy string) ([]Entry, error) {
+	v := url.Values{}
+	if filter != "" {
+		v.Set("filter", filter)
+	}
+	if sortBy != "" {
+		v.Set("sort", sortBy)
+	}
+	path := "/todos"
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+	var entries []Entry
+	return entries, c.do(http.MethodGet, path, nil, &entries)
+}
+
+// Tree fetches the whole list and renders the items matching filter as
+// List.Tree does.
+func (c *Client) Tree(filter, sortBy string) (string, error) {
+	q, err := ParseQuery(filter, sortBy)
+	if err != nil {
+		return "", err
+	}
+	entries, err := c.List("", "")
+	if err != nil {
+		return "", err
+	}
+	l := make(List, len(entries))
+	for _, e := range entries {
+		if e.Position < 1 || e.Position > len(l) {
+			return "", fmt.Errorf("%w: position %d out of range", ErrRemote, e.Position)
+		}
+		l[e.Position-1] = e.item
+	}
+	return l.Tree(q), nil
+}
+
+func (c *Client) Get(ref string) (Entry, error) {
+	var e Entry
+	return e, c.do(http.MethodGet, "/todos/"+url.PathEscape(ref), nil, &e)
+}
+
+// Add adds task, parsed like List.Add, optionally as a subtask of parent.
+func (c *Client) Add(task, parent string) (Entry, error) {
+	var e Entry
+	return e, c.do(http.MethodPost, "/todos", addRequest{Task: task, Parent: parent}, &e)
+}
+
+// Update edits the item ref with changes written as for ParseChanges.
+func (c *Client) Update(ref, changes string) (Entry, error) {
+	var e Entry
+	return e, c.do(http.MethodPatch, "/todos/"+url.PathEscape(ref), updateRequest{Changes: changes}, &e)
+}
+
+func (c *Client) Complete(ref string, force bool) (Entry, error) {
+	var e Entry
+	path := "/todos/" + url.PathEscape(ref) + "/complete"
+	if force {
+		path += "?force=" + strconv.FormatBool(force)
+	}
+	return e, c.do(http.MethodPost, path, nil, &e)
+}
+
+func (c *Client) Delete(ref string) error {
+	return c.do(http.MethodDelete, "/todos/"+url.PathEscape(ref), nil, nil)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+var (
+	errNoPassphrase       = errors.New("Set TODO_NEW_PASSPHRASE or run from a terminal to enter the new passphrase")
+	errPassphraseMismatch = errors.New("Passphrases do not match")
+)
+
+// newPassphrase returns the passphrase to rekey with, taken from
+// TODO_NEW_PASSPHRASE when set, even to an empty value, or else read
+// twice from the terminal without echoing it.
+func newPassphrase(in *os.File, out io.Writer) (string, error) {
+	if p, ok := os.LookupEnv("TODO_NEW_PASSPHRASE"); ok {
+		return p, nil
+	}
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errNoPassphrase
+	}
+
+	fmt.Fprint(out, "New passphrase (empty to decrypt): ")
+	p, err := term.ReadPassword(fd)
+	fmt.Fprintln(out)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprint(out, "Repeat passphrase: ")
+	again, err := term.ReadPassword(fd)
+	fmt.Fprintln(out)
+	if err != nil {
+		return "", err
+	}
+	if string(p) != string(again) {
+		return "", errPassphraseMismatch
+	}
+	return string(p), nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+var errEditAborted = errors.New("Edit aborted, no text left")
+
+// editTask applies the changes in args to the item ref. Without args the
+// item is opened in the user's editor and replaced by the saved text.
+func editTask(history *todo.History, store todo.Store, ref string, args []string) error {
+	if len(args) > 0 {
+		changes, err := todo.ParseChanges(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		return history.Modify("edit", func(l *todo.List) error {
+			i, err := l.Lookup(ref)
+			if err != nil {
+				return err
+			}
+			return l.Update(i, changes)
+		})
+	}
+
+	l := &todo.List{}
+	if err := store.Load(l); err != nil {
+		return err
+	}
+	i, err := l.Lookup(ref)
+	if err != nil {
+		return err
+	}
+	id := (*l)[i-1].ID
+	text, err := l.Text(i)
+	if err != nil {
+		return err
+	}
+
+	text, err = openEditor(text)
+	if err != nil {
+		return err
+	}
+	if text == "" {
+		return errEditAborted
+	}
+
+	return history.Modify("edit", func(l *todo.List) error {
+		i, err := l.Lookup(id)
+		if err != nil {
+			return err
+		}
+		return l.Update(i, todo.ParseReplacement(text))
+	})
+}
+
+// openEditor lets the user edit text in $VISUAL or $EDITOR, falling back
+// to vi, and returns the saved text joined into a single line. Lines
+// starting with # are ignored.
+func openEditor(text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "todo*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text + "\n# Edit the task above. Save an empty task to abort.\n"); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], f.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " "), nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func importICal(history *todo.History, fname string, stdin io.Reader) (int, error) {
+	r := stdin
+	if fname != "-" {
+		f, err := os.Open(fname)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	items, err := todo.ReadICal(r)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	err = history.Modify("import", func(l *todo.List) error {
+		before := len(*l)
+		l.Import(items)
+		n = len(*l) - before
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// exportICal writes the items matching filter as an iCalendar file, e.g.
+// only those with a due date for due:any.
+func exportICal(store todo.Store, fname, filter string, stdout io.Writer) error {
+	q, err := todo.ParseQuery(filter, "")
+	if err != nil {
+		return err
+	}
+	l := &todo.List{}
+	if err := store.Load(l); err != nil {
+		return err
+	}
+	var selected todo.List
+	for _, i := range l.Select(q) {
+		selected = append(selected, (*l)[i-1])
+	}
+
+	if fname == "-" {
+		return selected.WriteICal(stdout)
+	}
+	var buf bytes.Buffer
+	if err := selected.WriteICal(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(fname, buf.Bytes(), 0644)
+}
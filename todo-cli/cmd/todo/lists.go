@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+// listFilter returns the query terms restricting a listing to the named
+// list, or hiding archived lists from the combined view when name is
+// empty.
+func listFilter(c *todo.Catalog, name string) (string, error) {
+	if name != "" {
+		return " list:" + name, nil
+	}
+	archived, err := c.Archived()
+	if err != nil {
+		return "", err
+	}
+	filter := ""
+	for _, a := range archived {
+		filter += " -list:" + a
+	}
+	return filter, nil
+}
+
+func checkListOpen(c *todo.Catalog, name string) error {
+	archived, err := c.Archived()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(archived, name) {
+		return fmt.Errorf("%w: %q", todo.ErrListArchived, name)
+	}
+	return nil
+}
+
+func printLists(c *todo.Catalog, out io.Writer) error {
+	lists, err := c.Lists()
+	if err != nil {
+		return err
+	}
+	for _, li := range lists {
+		archived := ""
+		if li.Archived {
+			archived = " (archived)"
+		}
+		fmt.Fprintf(out, "%s: %d open, %d total%s\n", li.Name, li.Open, li.Total, archived)
+	}
+	return nil
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/itsjayeshrathi/todo-cli"
 )
@@ -34,6 +36,24 @@ func getTask(r io.Reader, args ...string) ([]string, error) {
 	return tasks, nil
 }
 
+// openStore picks the backend from kind when given, otherwise from the
+// scheme of name, e.g. TODO_FILENAME=bolt:///home/me/todo.db.
+func openStore(name, kind string) (todo.Store, error) {
+	if kind != "" {
+		return todo.OpenKind(kind, name)
+	}
+	return todo.Open(name)
+}
+
+// archiveCutoff returns the completion time before which tasks are
+// archived when keeping the last days days of completed tasks.
+func archiveCutoff(days int) time.Time {
+	if days <= 0 {
+		return time.Time{}
+	}
+	return time.Now().AddDate(0, 0, -days)
+}
+
 func main() {
 
 	flag.Usage = func() {
@@ -41,71 +61,238 @@ func main() {
 			"%s tool. Developed for The Pragmatic Bookshelf\n", os.Args[0])
 		fmt.Fprintf(flag.CommandLine.Output(), "Copyright 2020\n")
 		fmt.Fprintln(flag.CommandLine.Output(), "Usage information:")
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] [filter]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s serve [-addr host:port] [-store kind]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s merge base ours theirs\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "  %s watch [-sink spec]... [-interval d] [-lead d] [-repeat d] [-once]\n", os.Args[0])
 		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "Environment:")
+		fmt.Fprintln(flag.CommandLine.Output(), "  TODO_FILENAME       todo file, optionally prefixed with a store scheme such as bolt://")
+		fmt.Fprintf(flag.CommandLine.Output(), "  TODO_HISTORY_DEPTH  number of operations kept for -undo (default %d)\n", todo.DefaultHistoryDepth)
+		fmt.Fprintln(flag.CommandLine.Output(), "  TODO_AUTO_ARCHIVE   archive tasks completed more than this many days ago with every change")
+		fmt.Fprintln(flag.CommandLine.Output(), "  TODO_PASSPHRASE     passphrase to encrypt the todo file with")
+		fmt.Fprintln(flag.CommandLine.Output(), "  TODO_NEW_PASSPHRASE new passphrase for -rekey, prompted for when unset")
+		fmt.Fprintln(flag.CommandLine.Output(), "  TODO_REMOTE         URL of a todo server to use instead of the local file")
+		fmt.Fprintln(flag.CommandLine.Output(), "  TODO_TOKEN          shared token a todo server requires and the client sends")
 	}
 
 	if os.Getenv("TODO_FILENAME") != "" {
 		todoFileName = os.Getenv("TODO_FILENAME")
 	}
+	todo.Passphrase = os.Getenv("TODO_PASSPHRASE")
 
-	if _, err := os.Stat(todoFileName); os.IsNotExist(err) {
-		file, err := os.Create(todoFileName)
+	historyDepth := todo.DefaultHistoryDepth
+	if v := os.Getenv("TODO_HISTORY_DEPTH"); v != "" {
+		d, err := strconv.Atoi(v)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Unable to create file %s: %v\n", todoFileName, err)
+			fmt.Fprintf(os.Stderr, "Invalid TODO_HISTORY_DEPTH %q: %v\n", v, err)
 			os.Exit(1)
 		}
-		defer file.Close()
-		_, err = file.WriteString("[]")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Unable to initialize file content: %v\n", err)
+		historyDepth = d
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		if err := watch(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "merge" {
+		if err := merge(os.Args[2:], os.Stderr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
+		return
 	}
 
-	add := flag.Bool("add", false, "Add task to the Todo list")
-	list := flag.Bool("list", false, "List all tasks")
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := serve(os.Args[2:], historyDepth); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	add := flag.Bool("add", false, "Add task to the Todo list (accepts +tag, @context, !low|!medium|!high, due:YYYY-MM-DD and rec:daily|weekly[:mon,...]|monthly[:N]|every:N)")
+	list := flag.Bool("list", false, "List tasks, optionally matching a filter expression given as arguments")
 	ul := flag.Bool("ul", false, "List of all uncompleted tasks.")
-	complete := flag.Int("complete", 0, "Item to be completed")
-	delete := flag.Int("delete", 0, "Item to be deleted")
+	sortBy := flag.String("sort", "", "Sort listed tasks by position, created, completed, due, priority or task (prefix with - to reverse)")
+	ids := flag.Bool("ids", false, "Show item IDs when listing tasks")
+	tree := flag.Bool("tree", false, "List tasks as a tree of subtasks, showing blockers")
+	format := flag.String("format", "text", "Output format for listing: "+strings.Join(todo.Formats, ", "))
+	verbose := flag.Bool("verbose", false, "Show creation and completion timestamps when listing")
+	complete := flag.String("complete", "", "Item to be completed, by position or ID")
+	force := flag.Bool("force", false, "Complete a task even if it has open subtasks or blockers")
+	delete := flag.String("delete", "", "Item to be deleted, by position or ID")
+	edit := flag.String("edit", "", "Item to be edited, by position or ID. The new text and metadata are given as arguments, or in $EDITOR when omitted. Put -- before changes starting with -, e.g. -edit 1 -- -work")
+	parent := flag.String("parent", "", "Add the new tasks as subtasks of this item")
+	block := flag.String("block", "", "Mark this item as blocked by the item given with -by")
+	unblock := flag.String("unblock", "", "Remove the blocker given with -by from this item")
+	by := flag.String("by", "", "Blocking item for -block and -unblock")
+	archive := flag.Bool("archive", false, "Move completed tasks to the archive")
+	olderThan := flag.Int("older", 0, "Only archive tasks completed at least this many days ago")
+	archived := flag.Bool("archived", false, "List archived tasks instead of the current ones")
+	snooze := flag.String("snooze", "", "Hold back reminders for a task, by number or ID, until -until")
+	until := flag.String("until", "1h", "How long to snooze for, a duration such as 30m or a date")
+	interactive := flag.Bool("tui", false, "Browse and edit tasks in an interactive terminal UI, optionally matching a filter expression")
+	report := flag.Bool("report", false, "Show completion statistics")
+	days := flag.Int("days", 14, "Number of days covered by -report")
+	chart := flag.Bool("chart", false, "Add an ASCII burndown chart to -report")
+	listName := flag.String("l", "", "Named list to add to or list from, default is all lists when listing")
+	lists := flag.Bool("lists", false, "Show all named lists")
+	createList := flag.String("create-list", "", "Create a named list")
+	renameList := flag.String("rename-list", "", "Rename a named list to the name given with -to")
+	archiveList := flag.String("archive-list", "", "Archive a named list, hiding it from the combined view")
+	unarchiveList := flag.String("unarchive-list", "", "Restore an archived list")
+	move := flag.String("move", "", "Move this item to the list given with -to")
+	to := flag.String("to", "", "Target list for -move and -rename-list")
+
+	undo := flag.Bool("undo", false, "Undo the last add, complete, delete or import")
+	redo := flag.Bool("redo", false, "Redo the last undone operation")
+	importFile := flag.String("import", "", "Import tasks from a todo.txt file (- for STDIN)")
+	exportFile := flag.String("export", "", "Export tasks to a todo.txt file (- for STDOUT)")
+	rekey := flag.Bool("rekey", false, "Encrypt the todo file with a new passphrase, or decrypt it with an empty one")
+	importICS := flag.String("import-ics", "", "Import tasks from an iCalendar .ics file (- for STDIN)")
+	exportICS := flag.String("export-ics", "", "Export tasks, optionally matching a filter expression, to an iCalendar .ics file (- for STDOUT)")
+	storeKind := flag.String("store", "", "Storage backend for the todo file: json, jsonl, bolt or todotxt")
+	remote := flag.String("remote", os.Getenv("TODO_REMOTE"), "URL of a todo server to use instead of the local file")
 
 	flag.Parse()
 
-	l := &todo.List{}
+	if *remote != "" {
+		cmd := remoteCmd{
+			list:     *list,
+			archived: *archived,
+			tree:     *tree,
+			filter:   strings.Join(flag.Args(), " "),
+			args:     flag.Args(),
+			sortBy:   *sortBy,
+			add:      *add,
+			parent:   *parent,
+			complete: *complete,
+			force:    *force,
+			delete:   *delete,
+			edit:     *edit,
+			listName: *listName,
+			format: todo.FormatOptions{
+				Format:  *format,
+				IDs:     *ids,
+				Verbose: *verbose,
+			},
+		}
+		if *ul {
+			cmd.filter += " done:no"
+		}
+		if *listName != "" {
+			cmd.filter += " list:" + *listName
+		}
+		c := todo.NewClient(*remote)
+		c.Token = os.Getenv("TODO_TOKEN")
+		if err := runRemote(c, cmd, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	if err := l.Get(todoFileName); err != nil {
-		fmt.Fprintln(os.Stderr, "Error loading tasks: ", err)
+	store, err := openStore(todoFileName, *storeKind)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
+	}
+	history := todo.NewHistory(store, historyDepth)
+	catalog := todo.NewCatalog(history)
 
+	// Auto archiving is part of every change recorded in the history,
+	// so it is undone along with it and never runs on its own.
+	if v := os.Getenv("TODO_AUTO_ARCHIVE"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days <= 0 {
+			fmt.Fprintf(os.Stderr, "Invalid TODO_AUTO_ARCHIVE %q: must be a positive number of days\n", v)
+			os.Exit(1)
+		}
+		history.ArchiveAfter = time.Duration(days) * 24 * time.Hour
 	}
 
 	switch {
+	case *interactive:
+		filter := strings.Join(flag.Args(), " ")
+		if *ul {
+			filter += " done:no"
+		}
+		listFilter, err := listFilter(catalog, *listName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading lists: ", err)
+			os.Exit(1)
+		}
+		if err := runTUI(history, store, strings.TrimSpace(filter+listFilter), os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error running interactive mode: ", err)
+			os.Exit(1)
+		}
 	case *list:
+		l := &todo.List{}
+		src := store
+		if *archived {
+			src = todo.ArchiveStore(store)
+		}
+		if err := src.Load(l); err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading tasks: ", err)
+			os.Exit(1)
+		}
+		filter := strings.Join(flag.Args(), " ")
 		if *ul {
-			l.PrintIncomplete()
-		} else {
-			fmt.Print(l)
+			filter += " done:no"
 		}
-
-	case *complete > 0:
-
-		if err := l.Complete(*complete); err != nil {
-			fmt.Fprintln(os.Stderr, "Error finishing task: ", err)
+		listFilter, err := listFilter(catalog, *listName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading lists: ", err)
 			os.Exit(1)
 		}
-		if err := l.Save(todoFileName); err != nil {
-			fmt.Fprintln(os.Stderr, "Error saving tasks: ", err)
+		filter += listFilter
+		q, err := todo.ParseQuery(filter, *sortBy)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error parsing query: ", err)
+			os.Exit(1)
+		}
+		if *tree {
+			fmt.Print(l.Tree(q))
+			break
+		}
+		opts := todo.FormatOptions{Format: *format, IDs: *ids, Verbose: *verbose}
+		if err := todo.WriteEntries(os.Stdout, l.Entries(q), opts); err != nil {
+			fmt.Fprintln(os.Stderr, "Error listing tasks: ", err)
 			os.Exit(1)
 		}
-		fmt.Println("Task completed sucessfully.")
-
-	case *delete > 0:
 
-		if err := l.Delete(*delete); err != nil {
-			fmt.Fprintln(os.Stderr, "Error deleting task: ", err)
+	case *complete != "":
+		err := history.Modify("complete", func(l *todo.List) error {
+			i, err := l.Lookup(*complete)
+			if err != nil {
+				return err
+			}
+			if *force {
+				return l.ForceComplete(i)
+			}
+			return l.Complete(i)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error finishing task: ", err)
 			os.Exit(1)
 		}
-		if err := l.Save(todoFileName); err != nil {
-			fmt.Fprintln(os.Stderr, "Error saving tasks: ", err)
+		fmt.Println("Task completed sucessfully.")
+
+	case *delete != "":
+		err := history.Modify("delete", func(l *todo.List) error {
+			i, err := l.Lookup(*delete)
+			if err != nil {
+				return err
+			}
+			return l.Delete(i)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error deleting task: ", err)
 			os.Exit(1)
 		}
 		fmt.Println("Task deleted sucessfully.")
@@ -116,14 +303,192 @@ func main() {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		for _, item := range t {
-			l.Add(item)
+		if err := checkListOpen(catalog, *listName); err != nil {
+			fmt.Fprintln(os.Stderr, "Error saving tasks: ", err)
+			os.Exit(1)
 		}
-		if err := l.Save(todoFileName); err != nil {
+		err = history.Modify("add", func(l *todo.List) error {
+			p := 0
+			if *parent != "" {
+				var err error
+				if p, err = l.Lookup(*parent); err != nil {
+					return err
+				}
+			}
+			for _, item := range t {
+				l.Add(item)
+				if *listName != "" {
+					if err := l.Move(len(*l), *listName); err != nil {
+						return err
+					}
+				}
+				if p > 0 {
+					if err := l.SetParent(len(*l), p); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
 			fmt.Fprintln(os.Stderr, "Error saving tasks: ", err)
 			os.Exit(1)
 		}
 		fmt.Println("Task added successfully.")
+	case *edit != "":
+		if err := editTask(history, store, *edit, flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, "Error editing task: ", err)
+			os.Exit(1)
+		}
+		fmt.Println("Task updated successfully.")
+	case *block != "" || *unblock != "":
+		ref, op := *block, "block"
+		if ref == "" {
+			ref, op = *unblock, "unblock"
+		}
+		err := history.Modify(op, func(l *todo.List) error {
+			i, err := l.Lookup(ref)
+			if err != nil {
+				return err
+			}
+			on, err := l.Lookup(*by)
+			if err != nil {
+				return err
+			}
+			if op == "unblock" {
+				return l.RemoveDependency(i, on)
+			}
+			return l.AddDependency(i, on)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error updating dependency: ", err)
+			os.Exit(1)
+		}
+		fmt.Println("Dependency updated successfully.")
+	case *archive:
+		n, err := history.Archive(archiveCutoff(*olderThan))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error archiving tasks: ", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d tasks archived successfully.\n", n)
+	case *report:
+		l := &todo.List{}
+		if err := store.Load(l); err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading tasks: ", err)
+			os.Exit(1)
+		}
+		fmt.Print(l.Report(l.Stats(time.Now(), *days), *chart))
+	case *lists:
+		if err := printLists(catalog, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error loading lists: ", err)
+			os.Exit(1)
+		}
+	case *createList != "", *renameList != "", *archiveList != "", *unarchiveList != "":
+		var err error
+		switch {
+		case *createList != "":
+			err = catalog.Create(*createList)
+		case *renameList != "":
+			err = catalog.Rename(*renameList, *to)
+		case *archiveList != "":
+			err = catalog.Archive(*archiveList)
+		default:
+			err = catalog.Unarchive(*unarchiveList)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error updating list: ", err)
+			os.Exit(1)
+		}
+		fmt.Println("List updated successfully.")
+	case *move != "":
+		if *to == "" {
+			fmt.Fprintln(os.Stderr, "Error moving task: -move requires -to")
+			os.Exit(1)
+		}
+		if err := checkListOpen(catalog, *to); err != nil {
+			fmt.Fprintln(os.Stderr, "Error moving task: ", err)
+			os.Exit(1)
+		}
+		err := history.Modify("move", func(l *todo.List) error {
+			i, err := l.Lookup(*move)
+			if err != nil {
+				return err
+			}
+			return l.Move(i, *to)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error moving task: ", err)
+			os.Exit(1)
+		}
+		fmt.Println("Task moved successfully.")
+	case *snooze != "":
+		t, err := todo.ParseSnooze(*until, time.Now())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error parsing snooze time: ", err)
+			os.Exit(1)
+		}
+		err = history.Modify("snooze", func(l *todo.List) error {
+			i, err := l.Lookup(*snooze)
+			if err != nil {
+				return err
+			}
+			return l.Snooze(i, t)
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error snoozing task: ", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Task snoozed until %s.\n", t.Format("2006-01-02 15:04"))
+	case *rekey:
+		p, err := newPassphrase(os.Stdin, os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading passphrase: ", err)
+			os.Exit(1)
+		}
+		if err := todo.Rekey(store, p); err != nil {
+			fmt.Fprintln(os.Stderr, "Error rekeying: ", err)
+			os.Exit(1)
+		}
+		fmt.Println("Todo file rekeyed successfully.")
+	case *undo:
+		op, err := history.Undo()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error undoing: ", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Undid %s.\n", op)
+	case *redo:
+		op, err := history.Redo()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error redoing: ", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Redid %s.\n", op)
+	case *importFile != "":
+		n, err := importTodoTxt(history, *importFile, os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error importing tasks: ", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d tasks imported successfully.\n", n)
+	case *exportFile != "":
+		if err := exportTodoTxt(store, *exportFile, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error exporting tasks: ", err)
+			os.Exit(1)
+		}
+	case *importICS != "":
+		n, err := importICal(history, *importICS, os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error importing tasks: ", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d tasks imported successfully.\n", n)
+	case *exportICS != "":
+		if err := exportICal(store, *exportICS, strings.Join(flag.Args(), " "), os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error exporting tasks: ", err)
+			os.Exit(1)
+		}
 	default:
 		fmt.Fprintln(os.Stderr, "Invalid Option")
 		os.Exit(1)
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
 	"io"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
 )
 
 var (
@@ -33,6 +39,11 @@ func TestMain(m *testing.M) {
 	fmt.Println("Cleaning up...")
 	os.Remove(binName)
 	os.Remove(fileName)
+	os.Remove(fileName + ".lock")
+	os.Remove(fileName + ".history")
+	os.Remove(fileName + ".lists")
+	os.Remove(fileName + ".archive")
+	os.Remove(fileName + ".archive.lock")
 	os.Exit(result)
 }
 
@@ -89,4 +100,494 @@ func TestTodoCLI(t *testing.T) {
 		}
 
 	})
+	task3 := "call the vendor +work !high due:2026-11-01"
+	t.Run("AddTaskWithMetadata", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-add", task3)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to add task. Error: %v\nOutput: %s", err, out)
+		}
+		cmd = exec.Command(cmdPath, "-list")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := fmt.Sprintf(" 1: %s\n 2: %s\n 3: call the vendor !high due:2026-11-01 +work\n", task, task2)
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+	})
+	t.Run("ListFilteredTasks", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-list", "-sort", "-position", "+work")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := " 3: call the vendor !high due:2026-11-01 +work\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+	})
+	t.Run("CompleteTaskByID", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-list", "-ids", "+work")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		var id string
+		if _, err := fmt.Sscanf(string(out), " 3 (%8s): ", &id); err != nil {
+			t.Fatalf("Cannot find item ID in %q: %v", out, err)
+		}
+		cmd = exec.Command(cmdPath, "-complete", id)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to complete task. Error: %v\nOutput: %s", err, out)
+		}
+		cmd = exec.Command(cmdPath, "-list", "done:yes")
+		out, err = cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := "X 3: call the vendor !high due:2026-11-01 +work\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+	})
+	t.Run("ExportImportTodoTxt", func(t *testing.T) {
+		dir := t.TempDir()
+		txtFile := filepath.Join(dir, "todo.txt")
+		copyEnv := append(os.Environ(), "TODO_FILENAME="+filepath.Join(dir, "copy.json"))
+
+		cmd := exec.Command(cmdPath, "-export", txtFile)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to export tasks. Error: %v\nOutput: %s", err, out)
+		}
+		cmd = exec.Command(cmdPath, "-import", txtFile)
+		cmd.Env = copyEnv
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to import tasks. Error: %v\nOutput: %s", err, out)
+		}
+
+		expected, err := exec.Command(cmdPath, "-list", "-ids").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, expected)
+		}
+		cmd = exec.Command(cmdPath, "-list", "-ids")
+		cmd.Env = copyEnv
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		if string(expected) != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, out)
+		}
+
+		// Items replacing ones already in the list are not counted.
+		cmd = exec.Command(cmdPath, "-import", txtFile)
+		cmd.Env = copyEnv
+		out, err = cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to import tasks. Error: %v\nOutput: %s", err, out)
+		}
+		if exp := "0 tasks imported successfully.\n"; exp != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", exp, out)
+		}
+	})
+	t.Run("UndoRedoDelete", func(t *testing.T) {
+		before, err := exec.Command(cmdPath, "-list").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, before)
+		}
+		if out, err := exec.Command(cmdPath, "-delete", "1").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to delete task. Error: %v\nOutput: %s", err, out)
+		}
+		out, err := exec.Command(cmdPath, "-undo").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to undo. Error: %v\nOutput: %s", err, out)
+		}
+		if string(out) != "Undid delete.\n" {
+			t.Errorf("Expected %q, got %q instead", "Undid delete.\n", out)
+		}
+		after, err := exec.Command(cmdPath, "-list").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, after)
+		}
+		if string(before) != string(after) {
+			t.Errorf("Expected %q, got %q instead", before, after)
+		}
+		if out, err := exec.Command(cmdPath, "-redo").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to redo. Error: %v\nOutput: %s", err, out)
+		}
+		if out, err := exec.Command(cmdPath, "-undo").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to undo. Error: %v\nOutput: %s", err, out)
+		}
+	})
+	t.Run("RemoteMode", func(t *testing.T) {
+		store := todo.NewJSONStore(filepath.Join(t.TempDir(), "remote.json"))
+		ts := httptest.NewServer(todo.NewHandler(todo.NewHistory(store, 0)))
+		defer ts.Close()
+		env := append(os.Environ(), "TODO_REMOTE="+ts.URL)
+
+		cmd := exec.Command(cmdPath, "-add", "remote task +shared")
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to add task. Error: %v\nOutput: %s", err, out)
+		}
+		cmd = exec.Command(cmdPath, "-complete", "1")
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to complete task. Error: %v\nOutput: %s", err, out)
+		}
+		cmd = exec.Command(cmdPath, "-list", "+shared")
+		cmd.Env = env
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := "X 1: remote task +shared\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+
+		cmd = exec.Command(cmdPath, "-add", "-parent", "1", "remote subtask")
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to add subtask. Error: %v\nOutput: %s", err, out)
+		}
+		cmd = exec.Command(cmdPath, "-list", "-tree")
+		cmd.Env = env
+		out, err = cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tree. Error: %v\nOutput: %s", err, out)
+		}
+		expected = "X 1: remote task +shared\n   2: remote subtask\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+	})
+	t.Run("EditTask", func(t *testing.T) {
+		if out, err := exec.Command(cmdPath, "-edit", "2", "!high", "+review").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to edit task. Error: %v\nOutput: %s", err, out)
+		}
+		if out, err := exec.Command(cmdPath, "-edit", "2", "due:31-12-2026").CombinedOutput(); err == nil {
+			t.Errorf("Expected an invalid due date to fail, got %q", out)
+		}
+		if out, err := exec.Command(cmdPath, "-edit", "2", "--", "-unused", "+unused").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to edit tags after --. Error: %v\nOutput: %s", err, out)
+		}
+		if out, err := exec.Command(cmdPath, "-edit", "2", "--", "-unused").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to remove tag after --. Error: %v\nOutput: %s", err, out)
+		}
+
+		if runtime.GOOS == "windows" {
+			t.Skip("Editor script requires a POSIX shell")
+		}
+		editor := filepath.Join(t.TempDir(), "editor.sh")
+		script := "#!/bin/sh\nsed -i 's/test task number 2/renamed task/' \"$1\"\n"
+		if err := os.WriteFile(editor, []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command(cmdPath, "-edit", "2")
+		cmd.Env = append(os.Environ(), "VISUAL=", "EDITOR="+editor)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to edit task. Error: %v\nOutput: %s", err, out)
+		}
+
+		out, err := exec.Command(cmdPath, "-list", "+review").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := " 2: renamed task !high +review\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+	})
+	t.Run("NamedLists", func(t *testing.T) {
+		steps := [][]string{
+			{"-add", "-l", "chores", "sweep floor"},
+			{"-add", "post letter"},
+			{"-create-list", "errands"},
+			{"-move", "5", "-to", "errands"},
+			{"-archive-list", "errands"},
+		}
+		for _, args := range steps {
+			if out, err := exec.Command(cmdPath, args...).CombinedOutput(); err != nil {
+				t.Fatalf("Failed to run %v. Error: %v\nOutput: %s", args, err, out)
+			}
+		}
+		if out, err := exec.Command(cmdPath, "-add", "-l", "errands", "buy stamps").CombinedOutput(); err == nil {
+			t.Errorf("Expected adding to an archived list to fail, got %s", out)
+		}
+		if out, err := exec.Command(cmdPath, "-move", "4").CombinedOutput(); err == nil {
+			t.Errorf("Expected -move without -to to fail, got %s", out)
+		}
+		for _, args := range [][]string{{"-add", "-l", "my list", "x"}, {"-move", "4", "-to", "my list"}} {
+			if out, err := exec.Command(cmdPath, args...).CombinedOutput(); err == nil || !strings.Contains(string(out), "Invalid list name") {
+				t.Errorf("Expected %v to be rejected, got %s", args, out)
+			}
+		}
+
+		out, err := exec.Command(cmdPath, "-list", "-l", "chores").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := " 4: sweep floor list:chores\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+
+		out, err = exec.Command(cmdPath, "-list", "list:errands").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		if len(out) != 0 {
+			t.Errorf("Expected archived list to be hidden from the combined view, got %q", out)
+		}
+
+		out, err = exec.Command(cmdPath, "-lists").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to show lists. Error: %v\nOutput: %s", err, out)
+		}
+		expected = "chores: 1 open, 1 total\ndefault: 2 open, 3 total\nerrands: 1 open, 1 total (archived)\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+	})
+	t.Run("ListFormats", func(t *testing.T) {
+		out, err := exec.Command(cmdPath, "-list", "-format", "csv", "+review").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		r := csv.NewReader(bytes.NewReader(out))
+		records, err := r.ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 || records[0][3] != "Task" || records[1][3] != "renamed task" || records[1][6] != "+review" {
+			t.Errorf("Unexpected CSV output: %q", out)
+		}
+
+		if out, err := exec.Command(cmdPath, "-list", "-format", "xml").CombinedOutput(); err == nil {
+			t.Errorf("Expected invalid format to fail, got %q", out)
+		}
+	})
+	t.Run("ArchiveCompleted", func(t *testing.T) {
+		out, err := exec.Command(cmdPath, "-archive", "-older", "30").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to archive tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := "0 tasks archived successfully.\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+
+		if out, err := exec.Command(cmdPath, "-archive").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to archive tasks. Error: %v\nOutput: %s", err, out)
+		}
+
+		out, err = exec.Command(cmdPath, "-list", "+work").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		if len(out) != 0 {
+			t.Errorf("Expected archived task to be removed, got %q", out)
+		}
+
+		out, err = exec.Command(cmdPath, "-list", "-archived", "vendor").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list archived tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected = "X 1: call the vendor !high due:2026-11-01 +work\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+
+		out, err = exec.Command(cmdPath, "-undo").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to undo archiving. Error: %v\nOutput: %s", err, out)
+		}
+		if expected := "Undid archive.\n"; expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+		if out, _ := exec.Command(cmdPath, "-list", "-archived").CombinedOutput(); len(out) != 0 {
+			t.Errorf("Expected undo to empty the archive, got %q", out)
+		}
+		if out, err := exec.Command(cmdPath, "-redo").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to redo archiving. Error: %v\nOutput: %s", err, out)
+		}
+
+		cmd := exec.Command(cmdPath, "-list")
+		cmd.Env = append(os.Environ(), "TODO_AUTO_ARCHIVE=0")
+		if out, err := cmd.CombinedOutput(); err == nil {
+			t.Errorf("Expected TODO_AUTO_ARCHIVE=0 to be rejected, got %q", out)
+		}
+	})
+	t.Run("EncryptedFile", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-rekey")
+		cmd.Env = append(os.Environ(), "TODO_NEW_PASSPHRASE=secret")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to rekey. Error: %v\nOutput: %s", err, out)
+		}
+
+		if out, err := exec.Command(cmdPath, "-list").CombinedOutput(); err == nil {
+			t.Errorf("Expected listing without a passphrase to fail, got %q", out)
+		}
+
+		cmd = exec.Command(cmdPath, "-list", "+review")
+		cmd.Env = append(os.Environ(), "TODO_PASSPHRASE=secret")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := " 2: renamed task !high +review\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+
+		cmd = exec.Command(cmdPath, "-rekey")
+		cmd.Env = append(os.Environ(), "TODO_PASSPHRASE=secret", "TODO_NEW_PASSPHRASE=")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to decrypt. Error: %v\nOutput: %s", err, out)
+		}
+		if out, err := exec.Command(cmdPath, "-list").CombinedOutput(); err != nil {
+			t.Errorf("Expected decrypted file to be readable, got %v: %s", err, out)
+		}
+	})
+	t.Run("ExportImportICal", func(t *testing.T) {
+		out, err := exec.Command(cmdPath, "-export-ics", "-", "+review").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to export tasks. Error: %v\nOutput: %s", err, out)
+		}
+		for _, s := range []string{"BEGIN:VTODO\r\n", "SUMMARY:renamed task\r\n", "PRIORITY:1\r\n", "STATUS:NEEDS-ACTION\r\n"} {
+			if !bytes.Contains(out, []byte(s)) {
+				t.Errorf("Expected export to contain %q, got %q", s, out)
+			}
+		}
+		if bytes.Count(out, []byte("BEGIN:VTODO")) != 1 {
+			t.Errorf("Expected only the filtered task to be exported, got %q", out)
+		}
+
+		cmd := exec.Command(cmdPath, "-import-ics", "-")
+		cmd.Stdin = bytes.NewReader(out)
+		out, err = cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to import tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := "0 tasks imported successfully.\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+
+		cmd = exec.Command(cmdPath, "-import-ics", "-")
+		cmd.Stdin = strings.NewReader("BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VTODO\r\nUID:42\r\nSUMMARY:first foreign\r\nCATEGORIES:foreign\r\nEND:VTODO\r\n" +
+			"BEGIN:VTODO\r\nUID:42\r\nSUMMARY:second foreign\r\nCATEGORIES:foreign\r\nEND:VTODO\r\n" +
+			"END:VCALENDAR\r\n")
+		out, err = cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to import tasks. Error: %v\nOutput: %s", err, out)
+		}
+		if expected := "2 tasks imported successfully.\n"; expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+		out, err = exec.Command(cmdPath, "-list", "-ids", "+foreign").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		if bytes.Count(out, []byte("\n")) != 2 || bytes.Contains(out, []byte("(42)")) {
+			t.Errorf("Expected both foreign tasks under new IDs, got %q", out)
+		}
+		fields := strings.Fields(string(out))
+		if out, err := exec.Command(cmdPath, "-delete", strings.Trim(fields[1], "():")).CombinedOutput(); err != nil {
+			t.Errorf("Failed to delete by new ID. Error: %v\nOutput: %s", err, out)
+		}
+		if out, err := exec.Command(cmdPath, "-undo").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to undo. Error: %v\nOutput: %s", err, out)
+		}
+		if out, err := exec.Command(cmdPath, "-undo").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to undo. Error: %v\nOutput: %s", err, out)
+		}
+
+		out, err = exec.Command(cmdPath, "-list", "+review").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected = " 2: renamed task !high +review\n"
+		if expected != string(out) {
+			t.Errorf("Expected re-import to replace the task, got %q instead\n", string(out))
+		}
+	})
+	t.Run("MergeFiles", func(t *testing.T) {
+		dir := t.TempDir()
+		write := func(name, data string) string {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			return path
+		}
+		base := write("base.jsonl", `{"id":"aaaaaaaa","task":"call the vendor","done":false}`+"\n")
+		ours := write("ours.jsonl", `{"id":"aaaaaaaa","task":"call the vendor today","done":false}`+"\n")
+		theirs := write("theirs.jsonl", `{"id":"aaaaaaaa","task":"call the vendor","done":true}`+"\n")
+
+		if out, err := exec.Command(cmdPath, "merge", base, ours, theirs).CombinedOutput(); err != nil {
+			t.Fatalf("Failed to merge. Error: %v\nOutput: %s", err, out)
+		}
+		cmd := exec.Command(cmdPath, "-list")
+		cmd.Env = append(os.Environ(), "TODO_FILENAME="+ours)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		expected := "X 1: call the vendor today\n"
+		if expected != string(out) {
+			t.Errorf("Expected %q, got %q instead\n", expected, string(out))
+		}
+
+		theirs = write("theirs.jsonl", `{"id":"aaaaaaaa","task":"call the vendor tomorrow","done":false}`+"\n")
+		out, err = exec.Command(cmdPath, "merge", base, ours, theirs).CombinedOutput()
+		if err == nil {
+			t.Errorf("Expected conflicting merge to fail, got %q", out)
+		}
+		if !bytes.Contains(out, []byte("Conflict: aaaaaaaa task:")) {
+			t.Errorf("Expected conflict to be reported, got %q", out)
+		}
+	})
+	t.Run("WatchReminders", func(t *testing.T) {
+		if out, err := exec.Command(cmdPath, "-add", "water garden due:today").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to add task. Error: %v\nOutput: %s", err, out)
+		}
+		logFile := filepath.Join(t.TempDir(), "reminders.log")
+		watch := func() string {
+			t.Helper()
+			if out, err := exec.Command(cmdPath, "watch", "-once", "-sink", "file:"+logFile).CombinedOutput(); err != nil {
+				t.Fatalf("Failed to watch. Error: %v\nOutput: %s", err, out)
+			}
+			data, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(data)
+		}
+
+		log := watch()
+		if !bytes.Contains([]byte(log), []byte(`"water garden" is due today`)) {
+			t.Fatalf("Expected reminder to be logged, got %q", log)
+		}
+
+		out, err := exec.Command(cmdPath, "-list", "water").CombinedOutput()
+		if err != nil {
+			t.Fatalf("Failed to list tasks. Error: %v\nOutput: %s", err, out)
+		}
+		pos := string(bytes.TrimSuffix(bytes.Fields(out)[0], []byte(":")))
+		if out, err := exec.Command(cmdPath, "-snooze", pos, "-until", "tomorrow").CombinedOutput(); err != nil {
+			t.Fatalf("Failed to snooze task. Error: %v\nOutput: %s", err, out)
+		}
+		if again := watch(); again != log {
+			t.Errorf("Expected snoozed task not to be reminded of, got %q", again)
+		}
+
+		out, err = exec.Command(cmdPath, "watch", "-interval", "0").CombinedOutput()
+		if err == nil || !bytes.Contains(out, []byte("Watch interval must be positive")) {
+			t.Errorf("Expected -interval 0 to be rejected, got %q", out)
+		}
+	})
 }
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+var (
+	errMergeUsage     = errors.New("Usage: todo merge base ours theirs")
+	errMergeConflicts = errors.New("Merge has conflicts, our changes were kept")
+)
+
+// merge runs a three-way merge of todo files into ours, reporting
+// conflicts to out. It can be used as a git merge driver with
+//
+//	git config merge.todo.driver "todo merge %O %A %B"
+//
+// and a .gitattributes line such as ".todo.jsonl merge=todo".
+func merge(args []string, out io.Writer) error {
+	if len(args) != 3 {
+		return errMergeUsage
+	}
+	conflicts, err := todo.MergeFiles(args[0], args[1], args[2])
+	if err != nil {
+		return err
+	}
+	for _, c := range conflicts {
+		fmt.Fprintln(out, "Conflict:", c)
+	}
+	if len(conflicts) > 0 {
+		return errMergeConflicts
+	}
+	return nil
+}
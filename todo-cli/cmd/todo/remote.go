@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+var errRemoteUnsupported = errors.New("Option not supported with a remote server")
+
+// remoteCmd holds the command line options runRemote supports.
+type remoteCmd struct {
+	list     bool
+	archived bool
+	tree     bool
+	format   todo.FormatOptions
+	filter   string
+	args     []string
+	sortBy   string
+	add      bool
+	parent   string
+	complete string
+	force    bool
+	delete   string
+	edit     string
+	listName string
+}
+
+func serve(args []string, historyDepth int) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "Address to listen on")
+	storeKind := fs.String("store", "", "Storage backend for the todo file: json, jsonl, bolt or todotxt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := openStore(todoFileName, *storeKind)
+	if err != nil {
+		return err
+	}
+	h := todo.NewHandler(todo.NewHistory(store, historyDepth))
+	if token := os.Getenv("TODO_TOKEN"); token != "" {
+		h = todo.RequireToken(token, h)
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           h,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       time.Minute,
+	}
+	fmt.Printf("Serving %s on http://%s\n", store.Path(), *addr)
+	return srv.ListenAndServe()
+}
+
+func runRemote(c *todo.Client, cmd remoteCmd, in io.Reader, out io.Writer) error {
+	switch {
+	case cmd.list:
+		if cmd.archived {
+			return fmt.Errorf("Error loading tasks: %w", errRemoteUnsupported)
+		}
+		if cmd.tree {
+			tree, err := c.Tree(cmd.filter, cmd.sortBy)
+			if err != nil {
+				return fmt.Errorf("Error loading tasks: %w", err)
+			}
+			fmt.Fprint(out, tree)
+			return nil
+		}
+		entries, err := c.List(cmd.filter, cmd.sortBy)
+		if err != nil {
+			return fmt.Errorf("Error loading tasks: %w", err)
+		}
+		if err := todo.WriteEntries(out, entries, cmd.format); err != nil {
+			return fmt.Errorf("Error listing tasks: %w", err)
+		}
+	case cmd.complete != "":
+		if _, err := c.Complete(cmd.complete, cmd.force); err != nil {
+			return fmt.Errorf("Error finishing task: %w", err)
+		}
+		fmt.Fprintln(out, "Task completed sucessfully.")
+	case cmd.delete != "":
+		if err := c.Delete(cmd.delete); err != nil {
+			return fmt.Errorf("Error deleting task: %w", err)
+		}
+		fmt.Fprintln(out, "Task deleted sucessfully.")
+	case cmd.edit != "":
+		if len(cmd.args) == 0 {
+			return fmt.Errorf("Error editing task: %w", errRemoteUnsupported)
+		}
+		if _, err := c.Update(cmd.edit, strings.Join(cmd.args, " ")); err != nil {
+			return fmt.Errorf("Error editing task: %w", err)
+		}
+		fmt.Fprintln(out, "Task updated successfully.")
+	case cmd.add:
+		tasks, err := getTask(in, cmd.args...)
+		if err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if cmd.listName != "" {
+				t += " list:" + cmd.listName
+			}
+			if _, err := c.Add(t, cmd.parent); err != nil {
+				return fmt.Errorf("Error saving tasks: %w", err)
+			}
+		}
+		fmt.Fprintln(out, "Task added successfully.")
+	default:
+		return errRemoteUnsupported
+	}
+	return nil
+}
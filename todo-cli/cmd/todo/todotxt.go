@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func importTodoTxt(history *todo.History, fname string, stdin io.Reader) (int, error) {
+	r := stdin
+	if fname != "-" {
+		f, err := os.Open(fname)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	items, err := todo.ReadTodoTxt(r)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	err = history.Modify("import", func(l *todo.List) error {
+		before := len(*l)
+		l.Import(items)
+		n = len(*l) - before
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func exportTodoTxt(store todo.Store, fname string, stdout io.Writer) error {
+	l := &todo.List{}
+	if err := store.Load(l); err != nil {
+		return err
+	}
+	if fname == "-" {
+		return l.WriteTodoTxt(stdout)
+	}
+
+	var buf bytes.Buffer
+	if err := l.WriteTodoTxt(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(fname, buf.Bytes(), 0644)
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/itsjayeshrathi/todo-cli"
+	"golang.org/x/term"
+)
+
+var errNotTerminal = errors.New("Interactive mode needs a terminal")
+
+const tuiHelp = "j/k move  space toggle  e edit  a add  d delete  / filter  u undo  r redo  q quit"
+
+const (
+	modeList = iota
+	modeFilter
+	modeEdit
+	modeAdd
+	modeConfirm
+)
+
+// tui is the state of the interactive mode. Keys are fed to handle and
+// the screen is drawn by render, so it can be driven without a terminal.
+// Every change is saved through the history right away.
+type tui struct {
+	history *todo.History
+	store   todo.Store
+	filter  string
+	list    todo.List
+	entries []todo.Entry
+	cursor  int
+	offset  int
+	height  int
+	mode    int
+	input   []rune
+	status  string
+	quit    bool
+}
+
+func newTUI(history *todo.History, store todo.Store, filter string) (*tui, error) {
+	m := &tui{history: history, store: store, filter: filter, height: 24}
+	return m, m.reload()
+}
+
+// reload reads the list again and keeps the cursor within the entries
+// matching the filter.
+func (m *tui) reload() error {
+	q, err := todo.ParseQuery(m.filter, "")
+	if err != nil {
+		return err
+	}
+	l := todo.List{}
+	if err := m.store.Load(&l); err != nil {
+		return err
+	}
+	m.list = l
+	m.entries = l.Entries(q)
+	m.cursor = max(0, min(m.cursor, len(m.entries)-1))
+	return nil
+}
+
+// modify applies fn to the selected item. The item is looked up by ID,
+// so the change lands on it even if the file changed in the meantime.
+func (m *tui) modify(op string, fn func(l *todo.List, i int) error) error {
+	if len(m.entries) == 0 {
+		return nil
+	}
+	id := m.entries[m.cursor].ID
+	err := m.history.Modify(op, func(l *todo.List) error {
+		i, err := l.Lookup(id)
+		if err != nil {
+			return err
+		}
+		return fn(l, i)
+	})
+	if err != nil {
+		return err
+	}
+	return m.reload()
+}
+
+func (m *tui) prompt(mode int, text, status string) {
+	m.mode = mode
+	m.input = []rune(text)
+	m.status = status
+}
+
+func (m *tui) handle(key string) error {
+	if m.mode != modeList {
+		return m.handleInput(key)
+	}
+
+	m.status = ""
+	switch key {
+	case "q", "ctrl-c":
+		m.quit = true
+	case "j", "down":
+		m.cursor = min(m.cursor+1, max(0, len(m.entries)-1))
+	case "k", "up":
+		m.cursor = max(m.cursor-1, 0)
+	case "g", "home":
+		m.cursor = 0
+	case "G", "end":
+		m.cursor = max(0, len(m.entries)-1)
+	case " ", "x":
+		if len(m.entries) > 0 && m.entries[m.cursor].Done {
+			return m.modify("reopen", func(l *todo.List, i int) error { return l.Reopen(i) })
+		}
+		return m.modify("complete", func(l *todo.List, i int) error { return l.Complete(i) })
+	case "e", "enter":
+		if len(m.entries) == 0 {
+			return nil
+		}
+		text, err := m.list.Text(m.entries[m.cursor].Position)
+		if err != nil {
+			return err
+		}
+		m.prompt(modeEdit, text, "Edit: ")
+	case "a":
+		m.prompt(modeAdd, "", "Add: ")
+	case "d":
+		if len(m.entries) == 0 {
+			return nil
+		}
+		m.prompt(modeConfirm, "", fmt.Sprintf("Delete %q? (y/n) ", m.entries[m.cursor].Task))
+	case "/":
+		m.prompt(modeFilter, m.filter, "Filter: ")
+	case "u":
+		op, err := m.history.Undo()
+		if err != nil {
+			return err
+		}
+		m.status = "Undid " + op + "."
+		return m.reload()
+	case "r":
+		op, err := m.history.Redo()
+		if err != nil {
+			return err
+		}
+		m.status = "Redid " + op + "."
+		return m.reload()
+	}
+	return nil
+}
+
+// handleInput edits the prompt line and runs the pending action on enter.
+func (m *tui) handleInput(key string) error {
+	if m.mode == modeConfirm {
+		m.mode = modeList
+		m.status = ""
+		if key != "y" && key != "Y" {
+			return nil
+		}
+		return m.modify("delete", func(l *todo.List, i int) error { return l.Delete(i) })
+	}
+
+	switch key {
+	case "esc", "ctrl-c":
+		m.mode = modeList
+		m.status = ""
+		return nil
+	case "backspace":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+		return nil
+	case "enter":
+	default:
+		if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+			m.input = append(m.input, r[0])
+		}
+		return nil
+	}
+
+	mode, text := m.mode, strings.TrimSpace(string(m.input))
+	m.mode = modeList
+	m.status = ""
+	switch mode {
+	case modeFilter:
+		prev := m.filter
+		m.filter = text
+		if err := m.reload(); err != nil {
+			m.filter = prev
+			return err
+		}
+	case modeEdit:
+		if text == "" {
+			return errEditAborted
+		}
+		return m.modify("edit", func(l *todo.List, i int) error {
+			return l.Update(i, todo.ParseReplacement(text))
+		})
+	case modeAdd:
+		if text == "" {
+			return nil
+		}
+		if err := m.history.Modify("add", func(l *todo.List) error {
+			l.Add(text)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := m.reload(); err != nil {
+			return err
+		}
+		m.cursor = max(0, len(m.entries)-1)
+	}
+	return nil
+}
+
+// render draws the whole screen: a header, the visible part of the list
+// with the selected entry highlighted, and the status or prompt line.
+func (m *tui) render(w io.Writer) error {
+	rows := max(1, m.height-5)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&b, "todo: %d tasks", len(m.entries))
+	if m.filter != "" {
+		fmt.Fprintf(&b, " matching %q", m.filter)
+	}
+	b.WriteString("\r\n\r\n")
+	for k := m.offset; k < len(m.entries) && k < m.offset+rows; k++ {
+		if k == m.cursor {
+			fmt.Fprintf(&b, "\x1b[7m>%s\x1b[0m\r\n", m.entries[k])
+		} else {
+			fmt.Fprintf(&b, " %s\r\n", m.entries[k])
+		}
+	}
+	b.WriteString("\r\n")
+	if m.mode == modeList {
+		fmt.Fprintf(&b, "%s\r\n%s", m.status, tuiHelp)
+	} else {
+		fmt.Fprintf(&b, "%s%s", m.status, string(m.input))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var escapeKeys = map[string]string{
+	"[A": "up", "[B": "down", "[H": "home", "[F": "end",
+	"OA": "up", "OB": "down", "OH": "home", "OF": "end",
+	"[1~": "home", "[4~": "end",
+}
+
+// readKey reads one key press from a terminal in raw mode and returns
+// either the typed character or the name of a special key.
+func readKey(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 3:
+		return "ctrl-c", nil
+	case '\r', '\n':
+		return "enter", nil
+	case 8, 127:
+		return "backspace", nil
+	case 27:
+		var seq []byte
+		for r.Buffered() > 0 {
+			c, err := r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			seq = append(seq, c)
+			if len(seq) > 1 && (c == '~' || c >= 'A' && c <= 'Z') {
+				break
+			}
+		}
+		if len(seq) == 0 {
+			return "esc", nil
+		}
+		return escapeKeys[string(seq)], nil
+	}
+	if err := r.UnreadByte(); err != nil {
+		return "", err
+	}
+	c, _, err := r.ReadRune()
+	return string(c), err
+}
+
+// runTUI runs the interactive mode on the terminal in and out until the
+// user quits.
+func runTUI(history *todo.History, store todo.Store, filter string, in, out *os.File) error {
+	inFd, outFd := int(in.Fd()), int(out.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return errNotTerminal
+	}
+
+	m, err := newTUI(history, store, filter)
+	if err != nil {
+		return err
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(inFd, state)
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	r := bufio.NewReader(in)
+	for !m.quit {
+		if _, h, err := term.GetSize(outFd); err == nil {
+			m.height = h
+		}
+		if err := m.render(out); err != nil {
+			return err
+		}
+		key, err := readKey(r)
+		if err != nil {
+			return err
+		}
+		if err := m.handle(key); err != nil {
+			m.status = "Error: " + err.Error()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestReadKey(t *testing.T) {
+	input := "j\x1b[A\x1b[4~\r\x7f\x03é\x1b"
+	expected := []string{"j", "up", "end", "enter", "backspace", "ctrl-c", "é", "esc"}
+
+	r := bufio.NewReader(strings.NewReader(input))
+	for _, exp := range expected {
+		key, err := readKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != exp {
+			t.Errorf("Expected %q, got %q instead", exp, key)
+		}
+	}
+}
+
+func TestTUI(t *testing.T) {
+	store := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	history := todo.NewHistory(store, todo.DefaultHistoryDepth)
+	err := history.Modify("add", func(l *todo.List) error {
+		l.Add("first task")
+		l.Add("second task +work")
+		l.Add("third task")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := newTUI(history, store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			if err := m.handle(k); err != nil {
+				t.Fatalf("Key %q: %v", k, err)
+			}
+		}
+	}
+	load := func() todo.List {
+		t.Helper()
+		l := todo.List{}
+		if err := store.Load(&l); err != nil {
+			t.Fatal(err)
+		}
+		return l
+	}
+
+	t.Run("ToggleComplete", func(t *testing.T) {
+		press("j", " ")
+		if l := load(); !l[1].Done {
+			t.Errorf("Expected %q to be completed", l[1].Task)
+		}
+		press("x")
+		if l := load(); l[1].Done {
+			t.Errorf("Expected %q to be reopened", l[1].Task)
+		}
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		press("e")
+		if string(m.input) != "second task +work" {
+			t.Errorf("Expected prompt with task text, got %q instead", string(m.input))
+		}
+		press("backspace", "backspace", "backspace", "backspace", "h", "o", "m", "e", "enter")
+		if l := load(); l[1].Task != "second task" || l[1].Tags[0] != "home" {
+			t.Errorf("Expected edited task, got %q %v instead", l[1].Task, l[1].Tags)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		press("/", "+", "h", "o", "m", "e", "enter")
+		if len(m.entries) != 1 || m.entries[0].Position != 2 {
+			t.Errorf("Expected only item 2 to match, got %v instead", m.entries)
+		}
+		press("/", "backspace", "backspace", "backspace", "backspace", "backspace", "enter")
+		if len(m.entries) != 3 {
+			t.Errorf("Expected all items after clearing the filter, got %d instead", len(m.entries))
+		}
+	})
+
+	t.Run("DeleteWithConfirmation", func(t *testing.T) {
+		press("G", "d", "n")
+		if l := load(); len(l) != 3 {
+			t.Errorf("Expected delete to be cancelled, got %d items instead", len(l))
+		}
+		press("d", "y")
+		if l := load(); len(l) != 2 || l[1].Task != "second task" {
+			t.Errorf("Expected third task to be deleted, got %v instead", l)
+		}
+		if m.cursor != 1 {
+			t.Errorf("Expected cursor to move to the last item, got %d instead", m.cursor)
+		}
+	})
+
+	t.Run("AddAndUndo", func(t *testing.T) {
+		press("a", "n", "e", "w", "enter")
+		if l := load(); len(l) != 3 || l[2].Task != "new" {
+			t.Errorf("Expected new task to be added, got %v instead", l)
+		}
+		press("u")
+		if l := load(); len(l) != 2 {
+			t.Errorf("Expected add to be undone, got %d items instead", len(l))
+		}
+		if m.status != "Undid add." {
+			t.Errorf("Expected undo status, got %q instead", m.status)
+		}
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := m.render(&out); err != nil {
+			t.Fatal(err)
+		}
+		screen := out.String()
+		for _, s := range []string{"todo: 2 tasks", "  1: first task\r\n", "> 2: second task +home", tuiHelp} {
+			if !strings.Contains(screen, s) {
+				t.Errorf("Expected screen to contain %q, got %q", s, screen)
+			}
+		}
+	})
+
+	t.Run("Quit", func(t *testing.T) {
+		press("q")
+		if !m.quit {
+			t.Error("Expected q to quit")
+		}
+	})
+}
+
+func TestTUIToggleRecurring(t *testing.T) {
+	store := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	history := todo.NewHistory(store, todo.DefaultHistoryDepth)
+	err := history.Modify("add", func(l *todo.List) error {
+		l.Add("water plants rec:daily")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := newTUI(history, store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range []string{" ", " ", " ", " "} {
+		if err := m.handle(k); err != nil {
+			t.Fatalf("Key %q: %v", k, err)
+		}
+	}
+	l := todo.List{}
+	if err := store.Load(&l); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 1 || l[0].Done {
+		t.Errorf("Expected toggling twice to leave one open task, got %v instead", l)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+var errInvalidInterval = errors.New("Watch interval must be positive")
+
+// sinkFlags collects the repeatable -sink option.
+type sinkFlags []string
+
+func (s *sinkFlags) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *sinkFlags) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func watch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	var specs sinkFlags
+	fs.Var(&specs, "sink", "Where to send reminders: stdout, file:path, a webhook URL or exec:command (repeatable, default stdout)")
+	interval := fs.Duration("interval", time.Minute, "How often to check due dates")
+	lead := fs.Duration("lead", 0, "Remind this long before the start of the due day")
+	repeat := fs.Duration("repeat", 0, "Repeat reminders this often while a task stays due, 0 reminds once")
+	once := fs.Bool("once", false, "Check once and exit")
+	storeKind := fs.String("store", "", "Storage backend for the todo file: json, jsonl, bolt or todotxt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval <= 0 && !*once {
+		return fmt.Errorf("%w: %s", errInvalidInterval, *interval)
+	}
+	if len(specs) == 0 {
+		specs = sinkFlags{"stdout"}
+	}
+
+	var sinks []todo.Sink
+	for _, spec := range specs {
+		s, err := todo.ParseSink(spec)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, s)
+	}
+	store, err := openStore(todoFileName, *storeKind)
+	if err != nil {
+		return err
+	}
+	w := todo.NewWatcher(store, sinks...)
+	w.Lead = *lead
+	w.Repeat = *repeat
+
+	if *once {
+		return w.Check(time.Now())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if err := w.Check(time.Now()); err != nil {
+			fmt.Fprintln(os.Stderr, "Error sending reminders: ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
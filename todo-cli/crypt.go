@@ -0,0 +1,243 @@
+package todo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"os"
+	"sync"
+)
+
+// Passphrase, when set, encrypts every file written by List.Save and the
+// stores, including their history and list catalog. Files are encrypted
+// with AES-256-GCM under a key derived from the passphrase with
+// PBKDF2-SHA256. Plaintext files are still read, so setting a passphrase
+// encrypts existing files the next time they are saved.
+var Passphrase string
+
+const (
+	cryptMagic    = "TODOENC\x01"
+	kdfIterations = 600_000
+	// maxKDFIterations bounds the iterations read from a file header,
+	// which is only authenticated after the key is derived.
+	maxKDFIterations = 10 * kdfIterations
+	saltSize         = 16
+	headerSize       = len(cryptMagic) + 4 + saltSize
+)
+
+var derivedKeys struct {
+	sync.Mutex
+	passphrase string
+	salt       []byte
+	iter       int
+	key        []byte
+}
+
+// deriveKey returns the key for Passphrase and salt. The last key is
+// cached and its salt reused for writing, so a run reading and saving
+// several files derives it only once.
+func deriveKey(salt []byte, iter int) ([]byte, error) {
+	derivedKeys.Lock()
+	defer derivedKeys.Unlock()
+	d := &derivedKeys
+	if d.key != nil && d.passphrase == Passphrase && d.iter == iter && bytes.Equal(d.salt, salt) {
+		return d.key, nil
+	}
+	key, err := pbkdf2.Key(sha256.New, Passphrase, salt, iter, 32)
+	if err != nil {
+		return nil, err
+	}
+	d.passphrase, d.salt, d.iter, d.key = Passphrase, salt, iter, key
+	return key, nil
+}
+
+func writeSalt() ([]byte, error) {
+	derivedKeys.Lock()
+	defer derivedKeys.Unlock()
+	d := &derivedKeys
+	if d.key != nil && d.passphrase == Passphrase && d.iter == kdfIterations {
+		return d.salt, nil
+	}
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	return salt, err
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// IsEncrypted reports whether data was written with a passphrase.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(cryptMagic))
+}
+
+// encrypt seals data under Passphrase, returning it unchanged when no
+// passphrase is set. The header holds the KDF parameters and is
+// authenticated along with the data.
+func encrypt(data []byte) ([]byte, error) {
+	if Passphrase == "" {
+		return data, nil
+	}
+	salt, err := writeSalt()
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(salt, kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]byte{}, cryptMagic...)
+	header = binary.BigEndian.AppendUint32(header, kdfIterations)
+	header = append(header, salt...)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(header, nonce...)
+	return gcm.Seal(out, nonce, data, header), nil
+}
+
+// decrypt opens data sealed by encrypt. Data without the encryption
+// header is returned unchanged.
+func decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if Passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	if len(data) < headerSize {
+		return nil, ErrDecrypt
+	}
+	header := data[:headerSize]
+	iter := int(binary.BigEndian.Uint32(header[len(cryptMagic):]))
+	if iter < 1 || iter > maxKDFIterations {
+		return nil, ErrDecrypt
+	}
+	salt := header[len(cryptMagic)+4:]
+	key, err := deriveKey(bytes.Clone(salt), iter)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	rest := data[headerSize:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plain, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// readFile reads filename, decrypting it if needed.
+func readFile(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(data)
+}
+
+const rekeySuffix = ".rekey"
+
+// Rekey encrypts s, its history, list catalog and archive with
+// passphrase, which becomes the current Passphrase. An empty passphrase
+// stores them in plaintext. The files must be readable with the current
+// Passphrase.
+func Rekey(s Store, passphrase string) error {
+	type sidecar struct {
+		path string
+		data []byte
+	}
+	var (
+		stores   []Store
+		lists    []*List
+		sidecars []sidecar
+	)
+
+	for _, st := range []Store{s, ArchiveStore(s)} {
+		if _, err := os.Stat(st.Path()); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		unlock, err := st.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		l := &List{}
+		if err := st.Load(l); err != nil {
+			return err
+		}
+		stores = append(stores, st)
+		lists = append(lists, l)
+
+		for _, path := range []string{st.Path() + ".history", st.Path() + ".lists"} {
+			data, err := readFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			sidecars = append(sidecars, sidecar{path, data})
+		}
+	}
+
+	// Everything is written to temporary files first and only renamed
+	// into place once all writes succeeded, so a failure leaves the old
+	// files intact. Bolt stores also start from a fresh file that way,
+	// dropping freed pages holding the old contents.
+	old := Passphrase
+	Passphrase = passphrase
+	var written []string
+	fail := func(err error) error {
+		Passphrase = old
+		for _, path := range written {
+			os.Remove(path + rekeySuffix)
+		}
+		return err
+	}
+	for i, st := range stores {
+		tmp := st.Path() + rekeySuffix
+		if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fail(err)
+		}
+		written = append(written, st.Path())
+		if err := storeAt(st, tmp).Save(lists[i]); err != nil {
+			return fail(err)
+		}
+	}
+	for _, sc := range sidecars {
+		written = append(written, sc.path)
+		if err := writeFile(sc.path+rekeySuffix, sc.data); err != nil {
+			return fail(err)
+		}
+	}
+	for _, path := range written {
+		if err := os.Rename(path+rekeySuffix, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
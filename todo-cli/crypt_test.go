@@ -0,0 +1,186 @@
+package todo_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func setPassphrase(t *testing.T, p string) {
+	t.Helper()
+	old := todo.Passphrase
+	todo.Passphrase = p
+	t.Cleanup(func() { todo.Passphrase = old })
+}
+
+func TestEncryptedSaveGet(t *testing.T) {
+	setPassphrase(t, "correct horse")
+	fname := filepath.Join(t.TempDir(), ".todo.json")
+
+	l := todo.List{}
+	l.Add("call ACME Corp")
+	if err := l.Save(fname); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !todo.IsEncrypted(data) || bytes.Contains(data, []byte("ACME")) {
+		t.Errorf("Expected file to be encrypted, got %q", data)
+	}
+
+	l2 := todo.List{}
+	if err := l2.Get(fname); err != nil {
+		t.Fatal(err)
+	}
+	if l2[0].Task != "call ACME Corp" {
+		t.Errorf("Expected %q, got %q instead", "call ACME Corp", l2[0].Task)
+	}
+
+	testCases := []struct {
+		name       string
+		passphrase string
+		expErr     error
+	}{
+		{"NoPassphrase", "", todo.ErrPassphraseRequired},
+		{"WrongPassphrase", "battery staple", todo.ErrDecrypt},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setPassphrase(t, tc.passphrase)
+			l := todo.List{}
+			if err := l.Get(fname); !errors.Is(err, tc.expErr) {
+				t.Errorf("Expected error %q, got %q instead", tc.expErr, err)
+			}
+		})
+	}
+
+	t.Run("IterationsTooHigh", func(t *testing.T) {
+		bad := bytes.Clone(data)
+		copy(bad[len("TODOENC\x01"):], []byte{0xff, 0xff, 0xff, 0xff})
+		if err := os.WriteFile(fname, bad, 0644); err != nil {
+			t.Fatal(err)
+		}
+		l := todo.List{}
+		if err := l.Get(fname); !errors.Is(err, todo.ErrDecrypt) {
+			t.Errorf("Expected error %q, got %q instead", todo.ErrDecrypt, err)
+		}
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		data[len(data)-1] ^= 1
+		if err := os.WriteFile(fname, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		l := todo.List{}
+		if err := l.Get(fname); !errors.Is(err, todo.ErrDecrypt) {
+			t.Errorf("Expected error %q, got %q instead", todo.ErrDecrypt, err)
+		}
+	})
+}
+
+func TestRekey(t *testing.T) {
+	testCases := []struct {
+		name   string
+		open   func(path string) todo.Store
+		suffix string
+	}{
+		{"json", func(p string) todo.Store { return todo.NewJSONStore(p) }, ".json"},
+		{"bolt", func(p string) todo.Store { return todo.NewBoltStore(p) }, ".db"},
+		{"todotxt", func(p string) todo.Store { return todo.NewTodoTxtStore(p) }, ".txt"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.open(filepath.Join(t.TempDir(), "todo"+tc.suffix))
+			h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+			err := h.Modify("add", func(l *todo.List) error {
+				l.Add("call ACME Corp")
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := todo.NewCatalog(h).Create("customers"); err != nil {
+				t.Fatal(err)
+			}
+
+			setPassphrase(t, "")
+			if err := todo.Rekey(s, "first"); err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range []string{s.Path(), s.Path() + ".history", s.Path() + ".lists"} {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if bytes.Contains(data, []byte("ACME")) || bytes.Contains(data, []byte("customers")) {
+					t.Errorf("Expected %s to be encrypted", filepath.Base(path))
+				}
+			}
+
+			if err := todo.Rekey(s, "second"); err != nil {
+				t.Fatal(err)
+			}
+			todo.Passphrase = "first"
+			if err := s.Load(&todo.List{}); !errors.Is(err, todo.ErrDecrypt) {
+				t.Errorf("Expected old passphrase to fail with %q, got %q instead", todo.ErrDecrypt, err)
+			}
+
+			todo.Passphrase = "second"
+			if op, err := h.Undo(); err != nil || op != "create list" {
+				t.Errorf("Expected to undo create list with the new passphrase, got %q, %v instead", op, err)
+			}
+			if _, err := h.Redo(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := todo.Rekey(s, ""); err != nil {
+				t.Fatal(err)
+			}
+			if l := loadList(t, s); len(l) != 1 || l[0].Task != "call ACME Corp" {
+				t.Errorf("Expected list to survive rekeying, got %v instead", l)
+			}
+		})
+	}
+}
+
+func TestRekeyFailureKeepsFiles(t *testing.T) {
+	setPassphrase(t, "first")
+	s := todo.NewBoltStore(filepath.Join(t.TempDir(), "todo.db"))
+	h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+	err := h.Modify("add", func(l *todo.List) error {
+		l.Add("call ACME Corp")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory in the way of the history's temporary file makes
+	// writing it fail after the store itself was written.
+	if err := os.MkdirAll(filepath.Join(s.Path()+".history.rekey", "x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := todo.Rekey(s, "second"); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if todo.Passphrase != "first" {
+		t.Errorf("Expected passphrase %q to be kept, got %q instead", "first", todo.Passphrase)
+	}
+	if _, err := os.Stat(s.Path() + ".rekey"); !os.IsNotExist(err) {
+		t.Errorf("Expected temporary store to be removed, got %v", err)
+	}
+	if l := loadList(t, s); len(l) != 1 || l[0].Task != "call ACME Corp" {
+		t.Errorf("Expected store to be intact, got %v instead", l)
+	}
+	if op, err := h.Undo(); err != nil || op != "add" {
+		t.Errorf("Expected history to be intact, got %q, %v instead", op, err)
+	}
+}
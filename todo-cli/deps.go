@@ -0,0 +1,217 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SetParent makes item i a subtask of item parent. A parent of 0 turns i
+// back into a top level item.
+func (l *List) SetParent(i, parent int) error {
+	if err := l.check(i); err != nil {
+		return err
+	}
+	if parent == 0 {
+		(*l)[i-1].Parent = ""
+		return nil
+	}
+	if err := l.check(parent); err != nil {
+		return err
+	}
+
+	id := (*l)[i-1].ID
+	seen := map[string]bool{}
+	for p := parent; p > 0 && !seen[(*l)[p-1].ID]; p = l.indexOf((*l)[p-1].Parent) + 1 {
+		seen[(*l)[p-1].ID] = true
+		if (*l)[p-1].ID == id {
+			return fmt.Errorf("%w: %d cannot be a subtask of %d", ErrCycle, i, parent)
+		}
+	}
+	(*l)[i-1].Parent = (*l)[parent-1].ID
+	return nil
+}
+
+// AddDependency marks item i as blocked by item on.
+func (l *List) AddDependency(i, on int) error {
+	if err := l.check(i); err != nil {
+		return err
+	}
+	if err := l.check(on); err != nil {
+		return err
+	}
+
+	id, onID := (*l)[i-1].ID, (*l)[on-1].ID
+	if l.reaches(onID, id, map[string]bool{}) {
+		return fmt.Errorf("%w: %d already depends on %d", ErrCycle, on, i)
+	}
+	if !slices.Contains((*l)[i-1].BlockedBy, onID) {
+		(*l)[i-1].BlockedBy = append((*l)[i-1].BlockedBy, onID)
+	}
+	return nil
+}
+
+// breakCycles drops the parent and blocked-by links read from a file or
+// imported that would close a cycle, which SetParent and AddDependency
+// refuse to create. Links are re-added in list order, so the link that
+// closes a cycle is the one dropped. Links to items not in l are kept.
+func (l *List) breakCycles() {
+	parents := make([]string, len(*l))
+	blockers := make([][]string, len(*l))
+	for i := range *l {
+		t := &(*l)[i]
+		parents[i], blockers[i] = t.Parent, t.BlockedBy
+		t.Parent, t.BlockedBy = "", nil
+	}
+	for i, p := range parents {
+		if j := l.indexOf(p); j >= 0 {
+			l.SetParent(i+1, j+1)
+		} else {
+			(*l)[i].Parent = p
+		}
+	}
+	for i, bs := range blockers {
+		for _, b := range bs {
+			if j := l.indexOf(b); j >= 0 {
+				l.AddDependency(i+1, j+1)
+			} else if !slices.Contains((*l)[i].BlockedBy, b) {
+				(*l)[i].BlockedBy = append((*l)[i].BlockedBy, b)
+			}
+		}
+	}
+}
+
+// RemoveDependency removes the link marking item i as blocked by item on.
+func (l *List) RemoveDependency(i, on int) error {
+	if err := l.check(i); err != nil {
+		return err
+	}
+	if err := l.check(on); err != nil {
+		return err
+	}
+	t := &(*l)[i-1]
+	t.BlockedBy = slices.DeleteFunc(t.BlockedBy, func(id string) bool { return id == (*l)[on-1].ID })
+	if len(t.BlockedBy) == 0 {
+		t.BlockedBy = nil
+	}
+	return nil
+}
+
+func (l *List) check(i int) error {
+	if i <= 0 || i > len(*l) {
+		return fmt.Errorf("Item %d does not exist", i)
+	}
+	return nil
+}
+
+// reaches reports whether to can be reached from the item with ID from by
+// following blocked-by links.
+func (l *List) reaches(from, to string, seen map[string]bool) bool {
+	if from == to {
+		return true
+	}
+	if seen[from] {
+		return false
+	}
+	seen[from] = true
+	i := l.indexOf(from)
+	if i < 0 {
+		return false
+	}
+	for _, dep := range (*l)[i].BlockedBy {
+		if l.reaches(dep, to, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOpen returns an error if t has open subtasks or open blockers.
+func (l *List) checkOpen(t item) error {
+	for _, c := range *l {
+		if c.Parent == t.ID && !c.Done {
+			return fmt.Errorf("%w: %q", ErrOpenSubtasks, c.Task)
+		}
+	}
+	if b := l.blockers(t); len(b) > 0 {
+		return fmt.Errorf("%w: %q", ErrBlocked, (*l)[b[0]-1].Task)
+	}
+	return nil
+}
+
+// blockers returns the positions of the open items blocking t.
+func (l *List) blockers(t item) []int {
+	var pos []int
+	for _, id := range t.BlockedBy {
+		if i := l.indexOf(id); i >= 0 && !(*l)[i].Done {
+			pos = append(pos, i+1)
+		}
+	}
+	return pos
+}
+
+// unlink drops references to the deleted item id from the rest of l.
+func (l *List) unlink(id string) {
+	for i := range *l {
+		t := &(*l)[i]
+		if t.Parent == id {
+			t.Parent = ""
+		}
+		if slices.Contains(t.BlockedBy, id) {
+			t.BlockedBy = slices.DeleteFunc(t.BlockedBy, func(b string) bool { return b == id })
+			if len(t.BlockedBy) == 0 {
+				t.BlockedBy = nil
+			}
+		}
+	}
+}
+
+// Tree renders the items matching q with subtasks indented below their
+// parent. Items whose parent doesn't match q are shown at the top level.
+func (l *List) Tree(q *Query) string {
+	pos := l.Select(q)
+	shown := map[string]bool{}
+	for _, i := range pos {
+		shown[(*l)[i-1].ID] = true
+	}
+
+	var b strings.Builder
+	printed := map[string]bool{}
+	var show func(i, depth int)
+	show = func(i, depth int) {
+		t := (*l)[i-1]
+		printed[t.ID] = true
+		prefix := " "
+		if t.Done {
+			prefix = "X "
+		}
+		fmt.Fprintf(&b, "%s%s%d: %s", prefix, strings.Repeat("  ", depth), i, t.label())
+		if blocked := l.blockers(t); len(blocked) > 0 {
+			refs := make([]string, len(blocked))
+			for k, p := range blocked {
+				refs[k] = strconv.Itoa(p)
+			}
+			fmt.Fprintf(&b, " [blocked by %s]", strings.Join(refs, ", "))
+		}
+		b.WriteString("\n")
+		for _, c := range pos {
+			if sub := (*l)[c-1]; !printed[sub.ID] && sub.Parent == t.ID {
+				show(c, depth+1)
+			}
+		}
+	}
+	for _, i := range pos {
+		t := (*l)[i-1]
+		if !printed[t.ID] && (t.Parent == "" || !shown[t.Parent]) {
+			show(i, 0)
+		}
+	}
+	// Items on a parent cycle have no root; start from the first one.
+	for _, i := range pos {
+		if !printed[(*l)[i-1].ID] {
+			show(i, 0)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package todo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func newDepsList(t *testing.T) todo.List {
+	t.Helper()
+	l := todo.List{}
+	for _, task := range []string{"release", "write notes", "tag build", "deploy"} {
+		l.Add(task)
+	}
+	if err := l.SetParent(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetParent(3, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.AddDependency(4, 3); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestCompleteWithSubtasks(t *testing.T) {
+	l := newDepsList(t)
+
+	if err := l.Complete(1); !errors.Is(err, todo.ErrOpenSubtasks) {
+		t.Fatalf("Expected error %q, got %q instead", todo.ErrOpenSubtasks, err)
+	}
+	if l[0].Done {
+		t.Error("Parent should not be completed while subtasks are open")
+	}
+
+	l.Complete(2)
+	l.Complete(3)
+	if err := l.Complete(1); err != nil {
+		t.Errorf("Expected parent to complete once subtasks are done, got %q", err)
+	}
+}
+
+func TestForceComplete(t *testing.T) {
+	l := newDepsList(t)
+	if err := l.ForceComplete(1); err != nil {
+		t.Fatal(err)
+	}
+	if !l[0].Done || l[1].Done {
+		t.Errorf("Expected only the parent to be completed, got %+v", l)
+	}
+}
+
+func TestCompleteBlocked(t *testing.T) {
+	l := newDepsList(t)
+	if err := l.Complete(4); !errors.Is(err, todo.ErrBlocked) {
+		t.Fatalf("Expected error %q, got %q instead", todo.ErrBlocked, err)
+	}
+	l.Complete(3)
+	if err := l.Complete(4); err != nil {
+		t.Errorf("Expected item to complete once unblocked, got %q", err)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	l := newDepsList(t)
+
+	testCases := []struct {
+		name string
+		fn   func() error
+	}{
+		{"SelfParent", func() error { return l.SetParent(1, 1) }},
+		{"ParentLoop", func() error { return l.SetParent(1, 2) }},
+		{"SelfDependency", func() error { return l.AddDependency(4, 4) }},
+		{"DependencyLoop", func() error { return l.AddDependency(3, 4) }},
+		{"IndirectDependencyLoop", func() error {
+			if err := l.AddDependency(3, 2); err != nil {
+				return err
+			}
+			return l.AddDependency(2, 4)
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.fn(); !errors.Is(err, todo.ErrCycle) {
+				t.Errorf("Expected error %q, got %q instead", todo.ErrCycle, err)
+			}
+		})
+	}
+}
+
+func TestDeleteUnlinks(t *testing.T) {
+	l := newDepsList(t)
+	l.Delete(3)
+	if len(l[2].BlockedBy) != 0 {
+		t.Errorf("Expected blocker to be removed, got %v", l[2].BlockedBy)
+	}
+	l.Delete(1)
+	if l[0].Parent != "" {
+		t.Errorf("Expected parent link to be removed, got %q", l[0].Parent)
+	}
+}
+
+func TestTree(t *testing.T) {
+	l := newDepsList(t)
+	l.Add("sub of notes")
+	l.SetParent(5, 2)
+	l.Complete(5)
+
+	q, err := todo.ParseQuery("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := " 1: release\n" +
+		"   2: write notes\n" +
+		"X     5: sub of notes\n" +
+		"   3: tag build\n" +
+		" 4: deploy [blocked by 3]\n"
+	if res := l.Tree(q); res != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, res)
+	}
+
+	q, err = todo.ParseQuery("done:no", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = " 1: release\n" +
+		"   2: write notes\n" +
+		"   3: tag build\n" +
+		" 4: deploy [blocked by 3]\n"
+	if res := l.Tree(q); res != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, res)
+	}
+}
+
+// newParentCycle returns a list whose first two items are each other's
+// parent, as a hand edited or imported file can contain.
+func newParentCycle(t *testing.T) todo.List {
+	t.Helper()
+	l := todo.List{}
+	js := `[{"id":"aaaaaaaa","task":"a","done":true,"parent":"bbbbbbbb"},
+		{"id":"bbbbbbbb","task":"b","done":true,"parent":"aaaaaaaa"},
+		{"id":"cccccccc","task":"c"}]`
+	if err := json.Unmarshal([]byte(js), &l); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestParentCycle(t *testing.T) {
+	l := newParentCycle(t)
+
+	q, err := todo.ParseQuery("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := " 3: c\n" +
+		"X 1: a\n" +
+		"X   2: b\n"
+	if res := l.Tree(q); res != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, res)
+	}
+
+	if err := l.SetParent(3, 1); err != nil {
+		t.Errorf("Expected to attach below a parent cycle, got %q", err)
+	}
+}
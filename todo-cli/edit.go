@@ -0,0 +1,144 @@
+package todo
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Changes describes an edit to an item. Nil fields are left untouched.
+// Tags, when set, replaces all tags before AddTags and RemoveTags apply.
+type Changes struct {
+	Task       *string
+	Priority   *Priority
+	Due        *time.Time
+	Recur      *string
+	List       *string
+	Tags       *[]string
+	AddTags    []string
+	RemoveTags []string
+}
+
+// ParseChanges reads an edit in the inline syntax accepted by Add. Any
+// plain text replaces the task text, +tag and @context add tags, -tag and
+// -@context remove them, list:name moves the item to another list, and
+// !none, due:none and rec:none clear the priority, due date and
+// recurrence. Invalid priorities, due dates and recurrences are errors
+// rather than text.
+func ParseChanges(text string) (Changes, error) {
+	var c Changes
+	var words []string
+	for _, w := range strings.Fields(text) {
+		switch {
+		case len(w) > 1 && (w[0] == '+' || w[0] == '@'):
+			c.AddTags = append(c.AddTags, strings.TrimPrefix(w, "+"))
+		case len(w) > 1 && w[0] == '-' && (isLetter(w[1]) || w[1] == '@' || w[1] == '+'):
+			c.RemoveTags = append(c.RemoveTags, strings.TrimPrefix(w[1:], "+"))
+		case len(w) > 1 && w[0] == '!':
+			p, err := ParsePriority(w[1:])
+			if err != nil {
+				return Changes{}, err
+			}
+			c.Priority = &p
+		case strings.HasPrefix(w, "due:"):
+			var d time.Time
+			if w[4:] != "none" {
+				var err error
+				if d, err = ParseDate(w[4:]); err != nil {
+					return Changes{}, err
+				}
+			}
+			c.Due = &d
+		case strings.HasPrefix(w, "rec:"):
+			var rule string
+			if w[4:] != "none" {
+				r, err := ParseRecurrence(w[4:])
+				if err != nil {
+					return Changes{}, err
+				}
+				rule = r.String()
+			}
+			c.Recur = &rule
+		case strings.HasPrefix(w, "list:") && len(w) > 5:
+			name := listName(w[5:])
+			c.List = &name
+		default:
+			words = append(words, w)
+		}
+	}
+	if len(words) > 0 {
+		task := strings.Join(words, " ")
+		c.Task = &task
+	}
+	return c, nil
+}
+
+// ParseReplacement reads text the way Add does and returns changes that
+// make an item match it exactly, clearing anything text leaves out.
+func ParseReplacement(text string) Changes {
+	t := parseTask(text)
+	tags := t.Tags
+	return Changes{
+		Task:     &t.Task,
+		Priority: &t.Priority,
+		Due:      &t.Due,
+		Recur:    &t.Recur,
+		List:     &t.List,
+		Tags:     &tags,
+	}
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// Update applies c to item i, keeping its ID and timestamps.
+func (l *List) Update(i int, c Changes) error {
+	if err := l.check(i); err != nil {
+		return err
+	}
+	if c.Task != nil && strings.TrimSpace(*c.Task) == "" {
+		return ErrEmptyTask
+	}
+
+	t := &(*l)[i-1]
+	if c.Task != nil {
+		t.Task = strings.TrimSpace(*c.Task)
+	}
+	if c.Priority != nil {
+		t.Priority = *c.Priority
+	}
+	if c.Due != nil {
+		t.Due = *c.Due
+	}
+	if c.Recur != nil {
+		t.Recur = *c.Recur
+	}
+	if c.List != nil {
+		t.List = *c.List
+	}
+	if c.Tags != nil {
+		t.Tags = slices.Clone(*c.Tags)
+	}
+	for _, tag := range c.AddTags {
+		if !slices.Contains(t.Tags, tag) {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+	t.Tags = slices.DeleteFunc(t.Tags, func(tag string) bool {
+		return slices.Contains(c.RemoveTags, tag)
+	})
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+	return nil
+}
+
+// Text returns item i in the inline syntax accepted by Add, suitable for
+// editing and passing back to ParseReplacement.
+func (l *List) Text(i int) (string, error) {
+	if err := l.check(i); err != nil {
+		return "", err
+	}
+	return (*l)[i-1].label(), nil
+}
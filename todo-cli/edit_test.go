@@ -0,0 +1,115 @@
+package todo_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestUpdate(t *testing.T) {
+	l := todo.List{}
+	l.Add("call vendor +work @phone !low due:2026-11-01")
+	created := l[0].CreatedAt
+	id := l[0].ID
+
+	testCases := []struct {
+		name    string
+		changes string
+		check   func(t *testing.T)
+	}{
+		{"Priority", "!high", func(t *testing.T) {
+			if l[0].Priority != todo.PriorityHigh || l[0].Task != "call vendor" {
+				t.Errorf("Unexpected item: %+v", l[0])
+			}
+		}},
+		{"Text", "call the new vendor", func(t *testing.T) {
+			if l[0].Task != "call the new vendor" || l[0].Priority != todo.PriorityHigh {
+				t.Errorf("Unexpected item: %+v", l[0])
+			}
+		}},
+		{"Tags", "+urgent -work -@phone", func(t *testing.T) {
+			if !slices.Equal(l[0].Tags, []string{"urgent"}) {
+				t.Errorf("Expected tags %v, got %v instead", []string{"urgent"}, l[0].Tags)
+			}
+		}},
+		{"Due", "due:2026-12-24", func(t *testing.T) {
+			if !l[0].Due.Equal(date(2026, 12, 24)) {
+				t.Errorf("Expected due date %s, got %s instead", date(2026, 12, 24), l[0].Due)
+			}
+		}},
+		{"Clear", "due:none !none", func(t *testing.T) {
+			if !l[0].Due.IsZero() || l[0].Priority != todo.PriorityNone {
+				t.Errorf("Expected due date and priority to be cleared: %+v", l[0])
+			}
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := todo.ParseChanges(tc.changes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := l.Update(1, changes); err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t)
+			if l[0].ID != id || !l[0].CreatedAt.Equal(created) {
+				t.Errorf("Expected ID and creation time to be kept: %+v", l[0])
+			}
+		})
+	}
+}
+
+func TestUpdateReplacement(t *testing.T) {
+	l := todo.List{}
+	l.Add("call vendor +work !low due:2026-11-01")
+
+	text, err := l.Text(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "call vendor !low due:2026-11-01 +work" {
+		t.Errorf("Unexpected text %q", text)
+	}
+
+	if err := l.Update(1, todo.ParseReplacement("email vendor +home")); err != nil {
+		t.Fatal(err)
+	}
+	text, _ = l.Text(1)
+	if text != "email vendor +home" {
+		t.Errorf("Expected item to match replacement exactly, got %q", text)
+	}
+}
+
+func TestUpdateErrors(t *testing.T) {
+	l := todo.List{}
+	l.Add("task")
+
+	if err := l.Update(1, todo.ParseReplacement("+tag-only")); !errors.Is(err, todo.ErrEmptyTask) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrEmptyTask, err)
+	}
+	if err := l.Update(2, todo.Changes{}); err == nil {
+		t.Error("Expected error updating a missing item")
+	}
+}
+
+func TestParseChangesErrors(t *testing.T) {
+	testCases := []struct {
+		changes string
+		expErr  error
+	}{
+		{"due:31-12-2026", todo.ErrInvalidDate},
+		{"new text rec:sometimes", todo.ErrInvalidRecurrence},
+		{"!urgent", todo.ErrInvalidPriority},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.changes, func(t *testing.T) {
+			if _, err := todo.ParseChanges(tc.changes); !errors.Is(err, tc.expErr) {
+				t.Errorf("Expected error %q, got %q instead", tc.expErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package todo
+
+import "errors"
+
+var (
+	ErrNotFound           = errors.New("Item does not exist")
+	ErrAmbiguousRef       = errors.New("Item reference is ambiguous")
+	ErrInvalidPriority    = errors.New("Invalid priority")
+	ErrInvalidDate        = errors.New("Invalid date")
+	ErrInvalidRecurrence  = errors.New("Invalid recurrence")
+	ErrInvalidFilter      = errors.New("Invalid filter")
+	ErrInvalidSortKey     = errors.New("Invalid sort key")
+	ErrInvalidFormat      = errors.New("Invalid output format")
+	ErrUnknownStore       = errors.New("Unknown store")
+	ErrOpenSubtasks       = errors.New("Item has open subtasks")
+	ErrBlocked            = errors.New("Item is blocked by open items")
+	ErrCycle              = errors.New("Link would create a cycle")
+	ErrEmptyTask          = errors.New("Task text cannot be empty")
+	ErrInvalidListName    = errors.New("Invalid list name")
+	ErrListExists         = errors.New("List already exists")
+	ErrListNotFound       = errors.New("List does not exist")
+	ErrListArchived       = errors.New("List is archived")
+	ErrInvalidRequest     = errors.New("Invalid request")
+	ErrRemote             = errors.New("Server error")
+	ErrUnauthorized       = errors.New("Missing or wrong server token")
+	ErrNothingToUndo      = errors.New("Nothing to undo")
+	ErrNothingToRedo      = errors.New("Nothing to redo")
+	ErrPassphraseRequired = errors.New("File is encrypted, passphrase required")
+	ErrDecrypt            = errors.New("Wrong passphrase or corrupted file")
+	ErrInvalidICal        = errors.New("Invalid iCalendar data")
+	ErrInvalidSink        = errors.New("Invalid reminder sink")
+	ErrNotify             = errors.New("Sending reminder failed")
+	ErrLocked             = errors.New("Store is locked by another process")
+)
@@ -0,0 +1,8 @@
+package todo
+
+// Exported for the tests of the lock used on platforms without flock.
+var (
+	ExclusiveLock  = exclusiveLock
+	BreakStaleLock = breakStaleLock
+	LockTimeout    = &lockTimeout
+)
@@ -0,0 +1,45 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFile replaces filename with data atomically by writing to a
+// temporary file in the same directory and renaming it over the target,
+// so readers never observe a partially written file. The data is
+// encrypted first when a Passphrase is set.
+func writeFile(filename string, data []byte) error {
+	data, err := encrypt(data)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// Lock takes an exclusive advisory lock guarding filename, blocking until
+// it is available. The lock is held on a separate filename.lock file since
+// Save replaces filename itself. Call the returned function to release it.
+func Lock(filename string) (func() error, error) {
+	return lockFile(filename + ".lock")
+}
@@ -0,0 +1,175 @@
+package todo
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const timestampLayout = "2006-01-02 15:04"
+
+// FormatOptions controls how WriteEntries renders a list.
+type FormatOptions struct {
+	// Format is one of text, table, json, csv or markdown.
+	Format string
+	// IDs adds item IDs to the text format; the other formats always
+	// include them.
+	IDs bool
+	// Verbose shows full timestamps instead of dates.
+	Verbose bool
+	// Now is the reference time for ages, time.Now when zero.
+	Now time.Time
+}
+
+var Formats = []string{"text", "table", "json", "csv", "markdown"}
+
+var columns = []string{"#", "ID", "Done", "Task", "Priority", "Due", "Tags", "List", "Created", "Completed", "Age"}
+
+// WriteEntries renders entries to w in the format chosen by opts.
+func WriteEntries(w io.Writer, entries []Entry, opts FormatOptions) error {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	switch opts.Format {
+	case "", "text":
+		return writeText(w, entries, opts)
+	case "table":
+		return writeTable(w, entries, opts)
+	case "json":
+		return writeJSON(w, entries)
+	case "csv":
+		return writeCSV(w, entries, opts)
+	case "markdown", "md":
+		return writeMarkdown(w, entries, opts)
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidFormat, opts.Format)
+}
+
+func writeText(w io.Writer, entries []Entry, opts FormatOptions) error {
+	for _, e := range entries {
+		line := e.line(opts.IDs)
+		if opts.Verbose {
+			line += " [created " + formatTime(e.CreatedAt, true)
+			if e.Done {
+				line += ", completed " + formatTime(e.CompletedAt, true)
+			}
+			line += "]"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, entries []Entry, opts FormatOptions) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, e := range entries {
+		fmt.Fprintln(tw, strings.Join(e.fields(opts), "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+func writeCSV(w io.Writer, entries []Entry, opts FormatOptions) error {
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	for _, e := range entries {
+		cw.Write(e.fields(opts))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeMarkdown(w io.Writer, entries []Entry, opts FormatOptions) error {
+	sep := make([]string, len(columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(columns, " | "))
+	fmt.Fprintf(w, "| %s |\n", strings.Join(sep, " | "))
+	for _, e := range entries {
+		fields := e.fields(opts)
+		for i, f := range fields {
+			fields[i] = strings.ReplaceAll(f, "|", `\|`)
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(fields, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fields returns the values for each of the columns.
+func (e Entry) fields(opts FormatOptions) []string {
+	done := ""
+	if e.Done {
+		done = "x"
+	}
+	priority := ""
+	if e.Priority != PriorityNone {
+		priority = e.Priority.String()
+	}
+	due := ""
+	if !e.Due.IsZero() {
+		due = e.Due.Format(dateLayout)
+	}
+	tags := make([]string, len(e.Tags))
+	for i, tag := range e.Tags {
+		tags[i] = tagToken(tag)
+	}
+	return []string{
+		strconv.Itoa(e.Position),
+		e.ID,
+		done,
+		e.Task,
+		priority,
+		due,
+		strings.Join(tags, " "),
+		e.List,
+		formatTime(e.CreatedAt, opts.Verbose),
+		formatTime(e.CompletedAt, opts.Verbose),
+		formatAge(e.CreatedAt, opts.Now),
+	}
+}
+
+func formatTime(t time.Time, verbose bool) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case verbose:
+		return t.Local().Format(timestampLayout)
+	}
+	return t.Local().Format(dateLayout)
+}
+
+// formatAge renders the time elapsed since t in its largest whole unit.
+func formatAge(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := now.Sub(t)
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return "0m"
+}
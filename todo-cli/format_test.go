@@ -0,0 +1,101 @@
+package todo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func formatEntries(t *testing.T) []todo.Entry {
+	t.Helper()
+	l := todo.List{}
+	items, err := todo.ReadTodoTxt(bytes.NewBufferString(
+		"(A) 2026-10-01 call vendor | supplier +work due:2026-11-01 id:aaaaaaaa\n" +
+			"x 2026-10-05 2026-10-02 water plants @home list:chores id:bbbbbbbb\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Import(items)
+	q, err := todo.ParseQuery("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l.Entries(q)
+}
+
+func TestWriteEntries(t *testing.T) {
+	now := time.Date(2026, 10, 11, 12, 0, 0, 0, time.Local)
+
+	testCases := []struct {
+		name    string
+		format  string
+		verbose bool
+		exp     string
+	}{
+		{"Text", "text", false,
+			" 1: call vendor | supplier !high due:2026-11-01 +work\n" +
+				"X 2: water plants @home list:chores\n"},
+		{"TextVerbose", "text", true,
+			" 1: call vendor | supplier !high due:2026-11-01 +work [created 2026-10-01 00:00]\n" +
+				"X 2: water plants @home list:chores [created 2026-10-02 00:00, completed 2026-10-05 00:00]\n"},
+		{"Table", "table", false,
+			"#  ID        Done  Task                    Priority  Due         Tags   List    Created     Completed   Age\n" +
+				"1  aaaaaaaa        call vendor | supplier  high      2026-11-01  +work          2026-10-01              10d\n" +
+				"2  bbbbbbbb  x     water plants                                  @home  chores  2026-10-02  2026-10-05  9d\n"},
+		{"CSV", "csv", false,
+			"#,ID,Done,Task,Priority,Due,Tags,List,Created,Completed,Age\n" +
+				"1,aaaaaaaa,,call vendor | supplier,high,2026-11-01,+work,,2026-10-01,,10d\n" +
+				"2,bbbbbbbb,x,water plants,,,@home,chores,2026-10-02,2026-10-05,9d\n"},
+		{"Markdown", "markdown", false,
+			"| # | ID | Done | Task | Priority | Due | Tags | List | Created | Completed | Age |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| 1 | aaaaaaaa |  | call vendor \\| supplier | high | 2026-11-01 | +work |  | 2026-10-01 |  | 10d |\n" +
+				"| 2 | bbbbbbbb | x | water plants |  |  | @home | chores | 2026-10-02 | 2026-10-05 | 9d |\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := todo.FormatOptions{Format: tc.format, Verbose: tc.verbose, Now: now}
+			if err := todo.WriteEntries(&out, formatEntries(t), opts); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.exp {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tc.exp, out.String())
+			}
+		})
+	}
+}
+
+func TestWriteEntriesJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := todo.WriteEntries(&out, formatEntries(t), todo.FormatOptions{Format: "json"}); err != nil {
+		t.Fatal(err)
+	}
+	var entries []todo.Entry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != "aaaaaaaa" || entries[1].Position != 2 || !entries[1].Done {
+		t.Errorf("Unexpected JSON entries: %+v", entries)
+	}
+
+	out.Reset()
+	if err := todo.WriteEntries(&out, nil, todo.FormatOptions{Format: "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "[]\n" {
+		t.Errorf("Expected empty JSON array, got %q", out.String())
+	}
+}
+
+func TestWriteEntriesInvalidFormat(t *testing.T) {
+	err := todo.WriteEntries(&bytes.Buffer{}, nil, todo.FormatOptions{Format: "yaml"})
+	if !errors.Is(err, todo.ErrInvalidFormat) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrInvalidFormat, err)
+	}
+}
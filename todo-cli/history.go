@@ -0,0 +1,274 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"time"
+)
+
+const DefaultHistoryDepth = 50
+
+// change records one item before and after an operation. Before is nil
+// for added items and After is nil for deleted ones. Pos is the item's
+// position in the list it was inserted into.
+type change struct {
+	Pos    int   `json:"pos"`
+	Before *item `json:"before,omitempty"`
+	After  *item `json:"after,omitempty"`
+}
+
+// entry is one operation in the journal. Archived holds the changes it
+// made to the archive store and Catalog those to the list catalog.
+type entry struct {
+	Op       string         `json:"op"`
+	Time     time.Time      `json:"time"`
+	Changes  []change       `json:"changes"`
+	Archived []change       `json:"archived,omitempty"`
+	Catalog  *catalogChange `json:"catalog,omitempty"`
+}
+
+// catalogChange records the list catalog before and after an operation.
+type catalogChange struct {
+	Before []ListInfo `json:"before"`
+	After  []ListInfo `json:"after"`
+}
+
+type journal struct {
+	Undo []entry `json:"undo"`
+	Redo []entry `json:"redo"`
+}
+
+// History keeps a journal of list mutations next to a store, in a file
+// named after the store's path with a .history suffix, so they can be
+// undone and redone. Only the last Depth operations are kept.
+type History struct {
+	store Store
+	Depth int
+	// ArchiveAfter, when positive, makes every operation recorded by
+	// Modify also archive the items completed longer ago, so that both
+	// are undone together.
+	ArchiveAfter time.Duration
+}
+
+func NewHistory(s Store, depth int) *History {
+	return &History{store: s, Depth: depth}
+}
+
+func (h *History) path() string {
+	return h.store.Path() + ".history"
+}
+
+func (h *History) load() (*journal, error) {
+	j := &journal{}
+	data, err := readFile(h.path())
+	if errors.Is(err, os.ErrNotExist) || len(data) == 0 {
+		return j, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return j, json.Unmarshal(data, j)
+}
+
+func (h *History) save(j *journal) error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return writeFile(h.path(), data)
+}
+
+// Modify is like the package level Modify but records the changes fn
+// makes under the name op so they can be undone.
+func (h *History) Modify(op string, fn func(l *List) error) error {
+	return h.modify(op, func(l *List, e *entry) error {
+		if err := fn(l); err != nil {
+			return err
+		}
+		if h.ArchiveAfter <= 0 {
+			return nil
+		}
+		_, err := h.archive(l, time.Now().Add(-h.ArchiveAfter), e)
+		return err
+	})
+}
+
+// Archive moves the items completed before cutoff to the archive store
+// as Archive does, recording it as an operation that can be undone.
+func (h *History) Archive(cutoff time.Time) (int, error) {
+	n := 0
+	err := h.modify("archive", func(l *List, e *entry) error {
+		var err error
+		n, err = h.archive(l, cutoff, e)
+		return err
+	})
+	return n, err
+}
+
+func (h *History) archive(l *List, cutoff time.Time, e *entry) (int, error) {
+	archived := l.archive(cutoff)
+	if len(archived) == 0 {
+		return 0, nil
+	}
+	err := Modify(ArchiveStore(h.store), func(a *List) error {
+		before := a.Clone()
+		a.Import(archived)
+		e.Archived = append(e.Archived, diff(before, *a)...)
+		return nil
+	})
+	return len(archived), err
+}
+
+func (h *History) modify(op string, fn func(l *List, e *entry) error) error {
+	return Modify(h.store, func(l *List) error {
+		before := l.Clone()
+		e := entry{Op: op}
+		if err := fn(l, &e); err != nil {
+			return err
+		}
+		e.Time, e.Changes = time.Now(), diff(before, *l)
+		if len(e.Changes) == 0 && len(e.Archived) == 0 && e.Catalog == nil || h.Depth <= 0 {
+			return nil
+		}
+
+		j, err := h.load()
+		if err != nil {
+			return err
+		}
+		j.Undo = append(j.Undo, e)
+		if len(j.Undo) > h.Depth {
+			j.Undo = j.Undo[len(j.Undo)-h.Depth:]
+		}
+		j.Redo = nil
+		return h.save(j)
+	})
+}
+
+// Undo reverts the most recent operation and returns its name.
+func (h *History) Undo() (string, error) {
+	return h.step(true)
+}
+
+// Redo reapplies the most recently undone operation and returns its name.
+func (h *History) Redo() (string, error) {
+	return h.step(false)
+}
+
+func (h *History) step(undo bool) (string, error) {
+	var op string
+	err := Modify(h.store, func(l *List) error {
+		j, err := h.load()
+		if err != nil {
+			return err
+		}
+		from, to := &j.Undo, &j.Redo
+		if !undo {
+			from, to = to, from
+		}
+		if len(*from) == 0 {
+			if undo {
+				return ErrNothingToUndo
+			}
+			return ErrNothingToRedo
+		}
+
+		e := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		l.apply(e.Changes, !undo)
+		if len(e.Archived) > 0 {
+			err := Modify(ArchiveStore(h.store), func(a *List) error {
+				a.apply(e.Archived, !undo)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if e.Catalog != nil {
+			lists := e.Catalog.After
+			if undo {
+				lists = e.Catalog.Before
+			}
+			if err := NewCatalog(h).save(lists); err != nil {
+				return err
+			}
+		}
+		*to = append(*to, e)
+		op = e.Op
+		return h.save(j)
+	})
+	return op, err
+}
+
+// Clone returns a deep copy of l.
+func (l List) Clone() List {
+	c := make(List, len(l))
+	for i, t := range l {
+		t.Tags = slices.Clone(t.Tags)
+		t.BlockedBy = slices.Clone(t.BlockedBy)
+		c[i] = t
+	}
+	return c
+}
+
+// diff returns the changes turning before into after, matching items by ID.
+func diff(before, after List) []change {
+	var changes []change
+	for i, t := range before {
+		j := after.indexOf(t.ID)
+		switch {
+		case j < 0:
+			changes = append(changes, change{Pos: i, Before: &before[i]})
+		case !reflect.DeepEqual(t, after[j]):
+			changes = append(changes, change{Pos: j, Before: &before[i], After: &after[j]})
+		}
+	}
+	for i, t := range after {
+		if before.indexOf(t.ID) < 0 {
+			changes = append(changes, change{Pos: i, After: &after[i]})
+		}
+	}
+	return changes
+}
+
+// apply replays changes forwards, or backwards to revert them. Removals
+// happen first and insertions last, in position order, so every item
+// lands back where it was recorded.
+func (l *List) apply(changes []change, forward bool) {
+	type step struct {
+		pos      int
+		from, to *item
+	}
+	steps := make([]step, len(changes))
+	for i, c := range changes {
+		steps[i] = step{c.Pos, c.Before, c.After}
+		if !forward {
+			steps[i].from, steps[i].to = c.After, c.Before
+		}
+	}
+
+	for _, s := range steps {
+		if s.from != nil && s.to == nil {
+			if i := l.indexOf(s.from.ID); i >= 0 {
+				*l = slices.Delete(*l, i, i+1)
+			}
+		}
+	}
+	for _, s := range steps {
+		if s.from != nil && s.to != nil {
+			if i := l.indexOf(s.from.ID); i >= 0 {
+				(*l)[i] = *s.to
+			}
+		}
+	}
+
+	slices.SortStableFunc(steps, func(a, b step) int { return a.pos - b.pos })
+	for _, s := range steps {
+		if s.from == nil && s.to != nil {
+			*l = slices.Insert(*l, min(s.pos, len(*l)), *s.to)
+		}
+	}
+}
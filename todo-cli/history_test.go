@@ -0,0 +1,129 @@
+package todo_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func loadList(t *testing.T, s todo.Store) todo.List {
+	t.Helper()
+	l := todo.List{}
+	if err := s.Load(&l); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestHistoryUndoRedo(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+
+	ops := []struct {
+		name string
+		fn   func(l *todo.List) error
+	}{
+		{"add", func(l *todo.List) error {
+			l.Add("task one")
+			l.Add("task two +work")
+			l.Add("task three")
+			return nil
+		}},
+		{"complete", func(l *todo.List) error { return l.Complete(3) }},
+		{"delete", func(l *todo.List) error { return l.Delete(2) }},
+		{"add", func(l *todo.List) error {
+			l.Add("task four")
+			return nil
+		}},
+	}
+
+	var snapshots []todo.List
+	snapshots = append(snapshots, loadList(t, s))
+	for _, op := range ops {
+		if err := h.Modify(op.name, op.fn); err != nil {
+			t.Fatal(err)
+		}
+		snapshots = append(snapshots, loadList(t, s))
+	}
+
+	for i := len(ops) - 1; i >= 0; i-- {
+		name, err := h.Undo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != ops[i].name {
+			t.Errorf("Expected to undo %q, got %q instead", ops[i].name, name)
+		}
+		if l := loadList(t, s); !reflect.DeepEqual(l, snapshots[i]) {
+			t.Errorf("After undoing %q expected %+v, got %+v instead", name, snapshots[i], l)
+		}
+	}
+	if _, err := h.Undo(); !errors.Is(err, todo.ErrNothingToUndo) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNothingToUndo, err)
+	}
+
+	for i := range ops {
+		if _, err := h.Redo(); err != nil {
+			t.Fatal(err)
+		}
+		if l := loadList(t, s); !reflect.DeepEqual(l, snapshots[i+1]) {
+			t.Errorf("After redoing %q expected %+v, got %+v instead", ops[i].name, snapshots[i+1], l)
+		}
+	}
+	if _, err := h.Redo(); !errors.Is(err, todo.ErrNothingToRedo) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNothingToRedo, err)
+	}
+}
+
+func TestHistoryDepth(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHistory(s, 2)
+
+	for _, task := range []string{"one", "two", "three"} {
+		err := h.Modify("add", func(l *todo.List) error {
+			l.Add(task)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for range 2 {
+		if _, err := h.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := h.Undo(); !errors.Is(err, todo.ErrNothingToUndo) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNothingToUndo, err)
+	}
+	if l := loadList(t, s); len(l) != 1 || l[0].Task != "one" {
+		t.Errorf("Expected only the first task to remain, got %+v", l)
+	}
+}
+
+func TestHistoryNewOperationClearsRedo(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+
+	add := func(task string) {
+		err := h.Modify("add", func(l *todo.List) error {
+			l.Add(task)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("one")
+	if _, err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	add("two")
+	if _, err := h.Redo(); !errors.Is(err, todo.ErrNothingToRedo) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNothingToRedo, err)
+	}
+}
@@ -0,0 +1,423 @@
+package todo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalUIDSuffix      = "@todo-cli"
+	icalDateLayout     = "20060102"
+	icalDateTimeLayout = "20060102T150405"
+	icalLineLength     = 75
+)
+
+// iCalendar priorities run from 1 (highest) to 9 (lowest).
+var icalPriorities = map[Priority]int{
+	PriorityHigh:   1,
+	PriorityMedium: 5,
+	PriorityLow:    9,
+}
+
+var icalWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RRule returns r as an iCalendar recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO.
+func (r Recurrence) RRule() string {
+	switch r.kind {
+	case "every":
+		return fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", r.days)
+	case "weekly":
+		if len(r.weekdays) == 0 {
+			return "FREQ=WEEKLY"
+		}
+		days := make([]string, len(r.weekdays))
+		for i, wd := range r.weekdays {
+			days[i] = icalWeekdays[wd]
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(days, ",")
+	case "monthly":
+		if r.days > 0 {
+			return fmt.Sprintf("FREQ=MONTHLY;BYMONTHDAY=%d", r.days)
+		}
+	}
+	return "FREQ=" + strings.ToUpper(r.kind)
+}
+
+// ParseRRule converts an iCalendar recurrence rule into a Recurrence.
+// Only rules that have an equivalent Recurrence are accepted.
+func ParseRRule(s string) (Recurrence, error) {
+	parts := map[string]string{}
+	for _, p := range strings.Split(strings.ToUpper(s), ";") {
+		k, v, _ := strings.Cut(p, "=")
+		parts[k] = v
+	}
+	interval := 1
+	if v, ok := parts["INTERVAL"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Recurrence{}, fmt.Errorf("%w: %q", ErrInvalidRecurrence, s)
+		}
+		interval = n
+	}
+	delete(parts, "INTERVAL")
+	delete(parts, "WKST")
+
+	switch {
+	case parts["FREQ"] == "DAILY" && len(parts) == 1:
+		if interval > 1 {
+			return Recurrence{kind: "every", days: interval}, nil
+		}
+		return Recurrence{kind: "daily"}, nil
+	case parts["FREQ"] == "WEEKLY" && len(parts) == 1:
+		if interval > 1 {
+			return Recurrence{kind: "every", days: 7 * interval}, nil
+		}
+		return Recurrence{kind: "weekly"}, nil
+	case parts["FREQ"] == "WEEKLY" && len(parts) == 2 && interval == 1 && parts["BYDAY"] != "":
+		var names []string
+		for _, d := range strings.Split(parts["BYDAY"], ",") {
+			i := slices.Index(icalWeekdays, d)
+			if i < 0 {
+				return Recurrence{}, fmt.Errorf("%w: %q", ErrInvalidRecurrence, s)
+			}
+			names = append(names, strings.ToLower(time.Weekday(i).String()[:3]))
+		}
+		return ParseRecurrence("weekly:" + strings.Join(names, ","))
+	case parts["FREQ"] == "MONTHLY" && len(parts) == 1 && interval == 1:
+		return Recurrence{kind: "monthly"}, nil
+	case parts["FREQ"] == "MONTHLY" && len(parts) == 2 && interval == 1 && parts["BYMONTHDAY"] != "":
+		return ParseRecurrence("monthly:" + parts["BYMONTHDAY"])
+	}
+	return Recurrence{}, fmt.Errorf("%w: %q", ErrInvalidRecurrence, s)
+}
+
+// WriteICal writes l as an iCalendar file with one VTODO per item.
+// Lists and snoozes are kept in X-TODO-LIST and X-TODO-SNOOZED-UNTIL
+// properties so they survive a round trip.
+func (l *List) WriteICal(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	prop := func(name, value string) {
+		line, limit := name+":"+value, icalLineLength
+		for len(line) > limit {
+			n := limit
+			for n > 0 && !utf8.RuneStart(line[n]) {
+				n--
+			}
+			bw.WriteString(line[:n] + "\r\n ")
+			line, limit = line[n:], icalLineLength-1
+		}
+		bw.WriteString(line + "\r\n")
+	}
+
+	prop("BEGIN", "VCALENDAR")
+	prop("VERSION", "2.0")
+	prop("PRODID", "-//itsjayeshrathi//todo-cli//EN")
+	uids := map[string]string{}
+	for _, t := range *l {
+		uids[t.ID] = t.icalUID()
+	}
+	uid := func(id string) string {
+		if u, ok := uids[id]; ok {
+			return u
+		}
+		return id + icalUIDSuffix
+	}
+	for _, t := range *l {
+		stamp := t.CreatedAt
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		prop("BEGIN", "VTODO")
+		prop("UID", uid(t.ID))
+		prop("DTSTAMP", icalTime(stamp))
+		if !t.CreatedAt.IsZero() {
+			prop("CREATED", icalTime(t.CreatedAt))
+		}
+		prop("SUMMARY", icalEscape(t.Task))
+		if !t.Due.IsZero() {
+			prop("DUE;VALUE=DATE", t.Due.Format(icalDateLayout))
+		}
+		if r, err := ParseRecurrence(t.Recur); err == nil {
+			prop("RRULE", r.RRule())
+		}
+		if p, ok := icalPriorities[t.Priority]; ok {
+			prop("PRIORITY", strconv.Itoa(p))
+		}
+		if len(t.Tags) > 0 {
+			tags := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				tags[i] = icalEscape(tag)
+			}
+			prop("CATEGORIES", strings.Join(tags, ","))
+		}
+		if t.Parent != "" {
+			prop("RELATED-TO;RELTYPE=PARENT", uid(t.Parent))
+		}
+		for _, id := range t.BlockedBy {
+			prop("RELATED-TO;RELTYPE=DEPENDS-ON", uid(id))
+		}
+		if t.List != "" {
+			prop("X-TODO-LIST", icalEscape(t.List))
+		}
+		if !t.SnoozedUntil.IsZero() {
+			prop("X-TODO-SNOOZED-UNTIL", icalTime(t.SnoozedUntil))
+		}
+		if t.Done {
+			prop("STATUS", "COMPLETED")
+			if !t.CompletedAt.IsZero() {
+				prop("COMPLETED", icalTime(t.CompletedAt))
+			}
+		} else {
+			prop("STATUS", "NEEDS-ACTION")
+		}
+		prop("END", "VTODO")
+	}
+	prop("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func (t item) icalUID() string {
+	if t.UID != "" {
+		return t.UID
+	}
+	return t.ID + icalUIDSuffix
+}
+
+func icalTime(t time.Time) string {
+	return t.UTC().Format(icalDateTimeLayout) + "Z"
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func icalEscape(s string) string {
+	return icalEscaper.Replace(s)
+}
+
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, " ", `\N`, " ")
+
+// ReadICal parses the VTODO components of an iCalendar file. Other
+// components are skipped, as are recurrence rules without an equivalent
+// Recurrence. Items exported by WriteICal keep their ID; all others get a
+// new one and keep their UID.
+func ReadICal(r io.Reader) (List, error) {
+	lines, err := icalLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("%w: missing BEGIN:VCALENDAR", ErrInvalidICal)
+	}
+
+	var (
+		l     List
+		t     *item
+		depth int
+	)
+	for _, line := range lines {
+		name, params, value := splitICalLine(line)
+		switch {
+		case name == "BEGIN" && t == nil && strings.EqualFold(value, "VTODO"):
+			t = &item{}
+			depth = 0
+		case name == "BEGIN" && t != nil:
+			depth++
+		case name == "END" && t != nil && depth > 0:
+			depth--
+		case name == "END" && t != nil:
+			l = append(l, *t)
+			t = nil
+		case t != nil && depth == 0:
+			if err := t.setICalProperty(name, params, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if t != nil {
+		return nil, fmt.Errorf("%w: unterminated VTODO", ErrInvalidICal)
+	}
+	l.resolveUIDs()
+	l.breakCycles()
+	return l, nil
+}
+
+// icalLines reads r and unfolds continuation lines.
+func icalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, s.Err()
+}
+
+// splitICalLine splits a content line into its upper cased name, its
+// parameters and its value.
+func splitICalLine(line string) (name string, params map[string]string, value string) {
+	quoted := false
+	i := strings.IndexFunc(line, func(r rune) bool {
+		if r == '"' {
+			quoted = !quoted
+		}
+		return r == ':' && !quoted
+	})
+	if i < 0 {
+		return "", nil, ""
+	}
+	head, value := line[:i], line[i+1:]
+	fields := strings.Split(head, ";")
+	params = map[string]string{}
+	for _, p := range fields[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(fields[0]), params, value
+}
+
+func (t *item) setICalProperty(name string, params map[string]string, value string) error {
+	switch name {
+	case "UID":
+		t.UID = value
+	case "SUMMARY":
+		t.Task = strings.TrimSpace(icalUnescaper.Replace(value))
+	case "CREATED":
+		t.CreatedAt, _ = parseICalTime(value, params)
+	case "COMPLETED":
+		t.CompletedAt, _ = parseICalTime(value, params)
+		t.Done = true
+	case "STATUS":
+		t.Done = strings.EqualFold(value, "COMPLETED")
+	case "DUE":
+		d, err := parseICalTime(value, params)
+		if err != nil {
+			return err
+		}
+		d = d.In(time.Local)
+		t.Due = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+	case "RRULE":
+		if r, err := ParseRRule(value); err == nil {
+			t.Recur = r.String()
+		}
+	case "PRIORITY":
+		p, _ := strconv.Atoi(value)
+		switch {
+		case p >= 1 && p <= 4:
+			t.Priority = PriorityHigh
+		case p == 5:
+			t.Priority = PriorityMedium
+		case p >= 6 && p <= 9:
+			t.Priority = PriorityLow
+		}
+	case "CATEGORIES":
+		for _, c := range splitICalList(value) {
+			if c = strings.TrimSpace(icalUnescaper.Replace(c)); c != "" && !slices.Contains(t.Tags, c) {
+				t.Tags = append(t.Tags, c)
+			}
+		}
+	case "RELATED-TO":
+		switch strings.ToUpper(params["RELTYPE"]) {
+		case "", "PARENT":
+			t.Parent = value
+		case "DEPENDS-ON":
+			t.BlockedBy = append(t.BlockedBy, value)
+		}
+	case "X-TODO-LIST":
+		t.List = listName(icalUnescaper.Replace(value))
+	case "X-TODO-SNOOZED-UNTIL":
+		t.SnoozedUntil, _ = parseICalTime(value, params)
+	}
+	return nil
+}
+
+// resolveUIDs turns the UIDs read into item IDs. UIDs written by
+// WriteICal become the ID again, unless another item already took it.
+// Other items get a new ID, since foreign UIDs may be numeric and be
+// mistaken for positions, and keep their UID. Links are mapped along.
+func (l *List) resolveUIDs() {
+	ids := map[string]string{}
+	for i := range *l {
+		t := &(*l)[i]
+		uid := t.UID
+		id, ours := strings.CutSuffix(uid, icalUIDSuffix)
+		_, numeric := strconv.Atoi(id)
+		if ours && id != "" && numeric != nil && !strings.ContainsAny(id, " \t") && l.indexOf(id) < 0 {
+			t.ID, t.UID = id, ""
+		} else {
+			t.ID = l.newID()
+		}
+		if _, ok := ids[uid]; !ok && uid != "" {
+			ids[uid] = t.ID
+		}
+	}
+	resolve := func(uid string) string {
+		if id, ok := ids[uid]; ok {
+			return id
+		}
+		return strings.TrimSuffix(uid, icalUIDSuffix)
+	}
+	for i := range *l {
+		t := &(*l)[i]
+		if t.Parent != "" {
+			t.Parent = resolve(t.Parent)
+		}
+		for j, id := range t.BlockedBy {
+			t.BlockedBy[j] = resolve(id)
+		}
+	}
+}
+
+// splitICalList splits a comma separated value, leaving escaped commas
+// in place.
+func splitICalList(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// parseICalTime parses a DATE or DATE-TIME value. Date-times without a
+// zone are taken in the TZID parameter's location, or local time.
+func parseICalTime(value string, params map[string]string) (time.Time, error) {
+	loc := time.Local
+	if tz, ok := params["TZID"]; ok {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	var (
+		t   time.Time
+		err error
+	)
+	switch {
+	case len(value) == len(icalDateLayout):
+		t, err = time.ParseInLocation(icalDateLayout, value, time.Local)
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(icalDateTimeLayout+"Z", value)
+	default:
+		t, err = time.ParseInLocation(icalDateTimeLayout, value, loc)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,250 @@
+package todo_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestReadICal(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:not a task\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:4f3e2d1c-external\r\n" +
+		"SUMMARY:call the vendor about the\r\n" +
+		"  invoice\\, again\r\n" +
+		"DUE;TZID=UTC:20261101T090000\r\n" +
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,TH\r\n" +
+		"PRIORITY:2\r\n" +
+		"CATEGORIES:work,@phone\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"SUMMARY:alarm\r\n" +
+		"END:VALARM\r\n" +
+		"END:VTODO\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"SUMMARY:water plants\r\n" +
+		"STATUS:COMPLETED\r\n" +
+		"COMPLETED:20261005T120000Z\r\n" +
+		"RRULE:FREQ=YEARLY\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	l, err := todo.ReadICal(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 2 {
+		t.Fatalf("Expected 2 items, got %d instead", len(l))
+	}
+
+	if l[0].ID == "" || l[0].UID != "4f3e2d1c-external" || l[0].Task != "call the vendor about the invoice, again" {
+		t.Errorf("Unexpected first item: %+v", l[0])
+	}
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	if !l[0].Due.Equal(due) || l[0].Recur != "weekly:mon,thu" || l[0].Priority != todo.PriorityHigh {
+		t.Errorf("Unexpected metadata on first item: %+v", l[0])
+	}
+	if !reflect.DeepEqual(l[0].Tags, []string{"work", "@phone"}) {
+		t.Errorf("Expected tags %v, got %v instead", []string{"work", "@phone"}, l[0].Tags)
+	}
+	completed := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+	if !l[1].Done || !l[1].CompletedAt.Equal(completed) || l[1].Recur != "" || l[1].ID == "" {
+		t.Errorf("Unexpected second item: %+v", l[1])
+	}
+}
+
+func TestReadICalForeignUIDs(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\nUID:42\r\nSUMMARY:first\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:42\r\nSUMMARY:second\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:release@example.com\r\nSUMMARY:release\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:aaaaaaaa@todo-cli\r\nSUMMARY:notes\r\nRELATED-TO:release@example.com\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:aaaaaaaa@todo-cli\r\nSUMMARY:copy of notes\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	l, err := todo.ReadICal(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 5 {
+		t.Fatalf("Expected 5 items, got %d instead", len(l))
+	}
+	seen := map[string]bool{}
+	for _, it := range l {
+		if _, err := strconv.Atoi(it.ID); err == nil || it.ID == "" || seen[it.ID] {
+			t.Errorf("Expected a fresh non-numeric ID, got %q", it.ID)
+		}
+		seen[it.ID] = true
+	}
+	if l[0].UID != "42" || l[1].UID != "42" {
+		t.Errorf("Expected foreign UIDs to be kept, got %q and %q", l[0].UID, l[1].UID)
+	}
+	if l[3].ID != "aaaaaaaa" || l[3].UID != "" || l[3].Parent != l[2].ID {
+		t.Errorf("Expected own UID as ID and parent mapped to the new ID, got %+v", l[3])
+	}
+	if l[4].UID != "aaaaaaaa@todo-cli" {
+		t.Errorf("Expected duplicate UID to be kept, got %q", l[4].UID)
+	}
+
+	var out bytes.Buffer
+	if err := l.WriteICal(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"UID:42\r\n", "UID:release@example.com\r\n", "RELATED-TO;RELTYPE=PARENT:release@example.com\r\n"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("Expected export to contain %q, got %q", s, out.String())
+		}
+	}
+}
+
+func TestICalReimport(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\nUID:release@example.com\r\nSUMMARY:release\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:notes@example.com\r\nSUMMARY:notes\r\nRELATED-TO:release@example.com\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	l := todo.List{}
+	for range 2 {
+		items, err := todo.ReadICal(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Import(items)
+	}
+	if len(l) != 2 {
+		t.Fatalf("Expected 2 items after importing twice, got %d instead", len(l))
+	}
+	if l[1].Parent != l[0].ID {
+		t.Errorf("Expected parent %q, got %q instead", l[0].ID, l[1].Parent)
+	}
+}
+
+func TestReadICalParentCycle(t *testing.T) {
+	input := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\nUID:a@example.com\r\nSUMMARY:a\r\nRELATED-TO;RELTYPE=PARENT:b@example.com\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:b@example.com\r\nSUMMARY:b\r\nRELATED-TO;RELTYPE=PARENT:a@example.com\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	l, err := todo.ReadICal(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l[0].Parent != l[1].ID || l[1].Parent != "" {
+		t.Errorf("Expected the link closing the cycle dropped, got %q and %q", l[0].Parent, l[1].Parent)
+	}
+}
+
+func TestReadICalInvalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"NotCalendar", "BEGIN:VCARD\r\nEND:VCARD\r\n"},
+		{"Unterminated", "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:x\r\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := todo.ReadICal(strings.NewReader(tc.input)); !errors.Is(err, todo.ErrInvalidICal) {
+				t.Errorf("Expected error %q, got %q instead", todo.ErrInvalidICal, err)
+			}
+		})
+	}
+}
+
+func TestICalRoundTrip(t *testing.T) {
+	l := todo.List{}
+	l.Add("call the vendor; discuss the invoice, the contract and the long list of open questions !high due:2026-11-01 rec:every:3 +work list:office")
+	l.Add("water plants +home rec:weekly:mon,thu")
+	l.Add("fix the güven ünit tests")
+	if err := l.SetParent(3, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.AddDependency(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Complete(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Snooze(1, time.Date(2026, 10, 30, 9, 30, 0, 0, time.Local)); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := l.WriteICal(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(out.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines folded at 75 octets, got %q", line)
+		}
+	}
+
+	got, err := todo.ReadICal(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(l) {
+		t.Fatalf("Expected %d items, got %d instead", len(l), len(got))
+	}
+	for i := range l {
+		exp := l[i]
+		exp.CreatedAt = exp.CreatedAt.Truncate(time.Second)
+		exp.CompletedAt = exp.CompletedAt.Truncate(time.Second)
+		g := got[i]
+		if !g.CreatedAt.Equal(exp.CreatedAt) || !g.CompletedAt.Equal(exp.CompletedAt) || !g.Due.Equal(exp.Due) ||
+			!g.SnoozedUntil.Equal(exp.SnoozedUntil) {
+			t.Errorf("Expected dates of %+v, got %+v instead", exp, g)
+		}
+		g.CreatedAt, g.CompletedAt, g.Due, g.SnoozedUntil = exp.CreatedAt, exp.CompletedAt, exp.Due, exp.SnoozedUntil
+		if !reflect.DeepEqual(g, exp) {
+			t.Errorf("Expected %+v, got %+v instead", exp, g)
+		}
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	testCases := []struct {
+		rule   string
+		exp    string
+		expErr error
+	}{
+		{rule: "FREQ=DAILY", exp: "daily"},
+		{rule: "FREQ=DAILY;INTERVAL=3", exp: "every:3"},
+		{rule: "FREQ=WEEKLY;INTERVAL=2", exp: "every:14"},
+		{rule: "FREQ=WEEKLY;BYDAY=TH,MO;WKST=MO", exp: "weekly:mon,thu"},
+		{rule: "freq=monthly", exp: "monthly"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=31", exp: "monthly:31"},
+		{rule: "FREQ=MONTHLY;BYDAY=1MO", expErr: todo.ErrInvalidRecurrence},
+		{rule: "FREQ=YEARLY", expErr: todo.ErrInvalidRecurrence},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.rule, func(t *testing.T) {
+			r, err := todo.ParseRRule(tc.rule)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("Expected error %q, got %q instead", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.String() != tc.exp {
+				t.Errorf("Expected %q, got %q instead", tc.exp, r.String())
+			}
+			back, err := todo.ParseRRule(r.RRule())
+			if err != nil || back.String() != tc.exp {
+				t.Errorf("Expected %q to round trip, got %q instead", r.RRule(), back.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package todo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var idEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// newID returns a random 8 character ID that is not already used in l.
+// IDs always contain a letter so they can't be mistaken for an index.
+func (l *List) newID() string {
+	b := make([]byte, 5)
+	for {
+		rand.Read(b)
+		id := idEncoding.EncodeToString(b)
+		if _, err := strconv.Atoi(id); err == nil {
+			continue
+		}
+		if l.indexOf(id) < 0 {
+			return id
+		}
+	}
+}
+
+func (l *List) indexOf(id string) int {
+	for i, t := range *l {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// migrate assigns IDs to items saved before IDs existed. The IDs are
+// derived from each item's position and contents rather than drawn at
+// random, so every load of the same file assigns the same IDs whether or
+// not one of them saved the result.
+func (l *List) migrate() {
+	for i := range *l {
+		t := &(*l)[i]
+		for n := 0; t.ID == ""; n++ {
+			sum := sha256.Sum256(fmt.Appendf(nil, "%d\x00%s\x00%d\x00%d", i, t.Task, t.CreatedAt.UnixNano(), n))
+			id := idEncoding.EncodeToString(sum[:5])
+			if _, err := strconv.Atoi(id); err == nil {
+				continue
+			}
+			if l.indexOf(id) < 0 {
+				t.ID = id
+			}
+		}
+	}
+}
+
+// Lookup resolves ref to the 1-based position of an item. ref is either
+// a position, an item ID or an unambiguous prefix of an item ID.
+func (l *List) Lookup(ref string) (int, error) {
+	if i, err := strconv.Atoi(ref); err == nil {
+		if i <= 0 || i > len(*l) {
+			return 0, fmt.Errorf("%w: %d", ErrNotFound, i)
+		}
+		return i, nil
+	}
+
+	ref = strings.ToLower(ref)
+	found := 0
+	for i, t := range *l {
+		if t.ID == ref {
+			return i + 1, nil
+		}
+		if ref != "" && strings.HasPrefix(t.ID, ref) {
+			if found != 0 {
+				return 0, fmt.Errorf("%w: %q", ErrAmbiguousRef, ref)
+			}
+			found = i + 1
+		}
+	}
+	if found == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrNotFound, ref)
+	}
+	return found, nil
+}
@@ -0,0 +1,84 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+)
+
+// ReadJSONL parses a list stored one JSON item per line.
+func ReadJSONL(r io.Reader) (List, error) {
+	var l List
+	dec := json.NewDecoder(r)
+	for {
+		var t item
+		err := dec.Decode(&t)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, t)
+	}
+	l.migrate()
+	return l, nil
+}
+
+// WriteJSONL writes l one JSON item per line in list order. The output
+// only depends on the items, so a change to one item changes one line.
+func (l *List) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, t := range *l {
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSONLStore keeps the list in a JSON Lines file, which diffs and merges
+// well under version control.
+type JSONLStore struct {
+	path string
+}
+
+func NewJSONLStore(path string) *JSONLStore {
+	return &JSONLStore{path: path}
+}
+
+func (s *JSONLStore) Load(l *List) error {
+	data, err := readFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		*l = (*l)[:0]
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	items, err := ReadJSONL(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+func (s *JSONLStore) Save(l *List) error {
+	var buf bytes.Buffer
+	if err := l.WriteJSONL(&buf); err != nil {
+		return err
+	}
+	return writeFile(s.path, buf.Bytes())
+}
+
+func (s *JSONLStore) Lock() (func() error, error) {
+	return Lock(s.path)
+}
+
+func (s *JSONLStore) Path() string {
+	return s.path
+}
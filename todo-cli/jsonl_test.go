@@ -0,0 +1,64 @@
+package todo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestJSONLRoundTrip(t *testing.T) {
+	input := `{"id":"aaaaaaaa","task":"call the vendor","done":false,"created_at":"2026-10-01T09:00:00Z","updated_at":"0001-01-01T00:00:00Z","priority":"high","tags":["work"]}
+{"id":"bbbbbbbb","task":"water plants","done":true,"created_at":"2026-10-02T09:00:00Z","updated_at":"2026-10-05T18:30:00Z","list":"home"}
+`
+	l, err := todo.ReadJSONL(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 2 || l[0].Task != "call the vendor" || l[0].Priority != todo.PriorityHigh || !l[1].Done {
+		t.Fatalf("Unexpected items: %+v", l)
+	}
+
+	var out bytes.Buffer
+	if err := l.WriteJSONL(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != input {
+		t.Errorf("Expected %q, got %q instead", input, out.String())
+	}
+}
+
+func TestJSONLOneLinePerItem(t *testing.T) {
+	l := todo.List{}
+	l.Add("first task")
+	l.Add("second task")
+	l.Add("third task")
+
+	var before bytes.Buffer
+	if err := l.WriteJSONL(&before); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Complete(2); err != nil {
+		t.Fatal(err)
+	}
+	var after bytes.Buffer
+	if err := l.WriteJSONL(&after); err != nil {
+		t.Fatal(err)
+	}
+
+	b := strings.Split(before.String(), "\n")
+	a := strings.Split(after.String(), "\n")
+	if len(a) != len(b) {
+		t.Fatalf("Expected %d lines, got %d instead", len(b), len(a))
+	}
+	changed := 0
+	for i := range a {
+		if a[i] != b[i] {
+			changed++
+		}
+	}
+	if changed != 1 {
+		t.Errorf("Expected completing an item to change 1 line, got %d instead", changed)
+	}
+}
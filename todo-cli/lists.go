@@ -0,0 +1,233 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// DefaultList is the name of the list holding items without a list.
+const DefaultList = "default"
+
+// listName maps the user facing name of a list to the value stored on
+// items, where the default list is the empty string.
+func listName(name string) string {
+	if name == DefaultList || name == "none" {
+		return ""
+	}
+	return name
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return DefaultList
+	}
+	return name
+}
+
+// ListInfo summarizes one named list.
+type ListInfo struct {
+	Name     string `json:"name"`
+	Archived bool   `json:"archived,omitempty"`
+	Open     int    `json:"-"`
+	Total    int    `json:"-"`
+}
+
+// Catalog manages the named lists kept in one store. Lists exist as soon
+// as an item uses them; the catalog file, named after the store's path
+// with a .lists suffix, records empty and archived lists.
+type Catalog struct {
+	history *History
+}
+
+func NewCatalog(h *History) *Catalog {
+	return &Catalog{history: h}
+}
+
+func (c *Catalog) path() string {
+	return c.history.store.Path() + ".lists"
+}
+
+func (c *Catalog) load() ([]ListInfo, error) {
+	var lists []ListInfo
+	data, err := readFile(c.path())
+	if errors.Is(err, os.ErrNotExist) || len(data) == 0 {
+		return lists, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lists, json.Unmarshal(data, &lists)
+}
+
+func (c *Catalog) save(lists []ListInfo) error {
+	data, err := json.Marshal(lists)
+	if err != nil {
+		return err
+	}
+	return writeFile(c.path(), data)
+}
+
+func findList(lists []ListInfo, name string) int {
+	return slices.IndexFunc(lists, func(li ListInfo) bool { return li.Name == name })
+}
+
+func validListName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n:") || listName(name) == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidListName, name)
+	}
+	return nil
+}
+
+// Lists returns every list in the store, including the default one, with
+// item counts, ordered by name.
+func (c *Catalog) Lists() ([]ListInfo, error) {
+	lists, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	l := &List{}
+	if err := c.history.store.Load(l); err != nil {
+		return nil, err
+	}
+
+	lists = append(lists, ListInfo{Name: DefaultList})
+	for _, t := range *l {
+		name := displayName(t.List)
+		i := findList(lists, name)
+		if i < 0 {
+			lists = append(lists, ListInfo{Name: name})
+			i = len(lists) - 1
+		}
+		lists[i].Total++
+		if !t.Done {
+			lists[i].Open++
+		}
+	}
+	slices.SortFunc(lists, func(a, b ListInfo) int { return strings.Compare(a.Name, b.Name) })
+	return lists, nil
+}
+
+// Archived returns the names of the archived lists.
+func (c *Catalog) Archived() ([]string, error) {
+	lists, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, li := range lists {
+		if li.Archived {
+			names = append(names, li.Name)
+		}
+	}
+	return names, nil
+}
+
+// update runs fn on the catalog and the list while holding the store lock,
+// recording the changes to both in the history.
+func (c *Catalog) update(op string, fn func(lists *[]ListInfo, l *List) error) error {
+	return c.history.modify(op, func(l *List, e *entry) error {
+		lists, err := c.load()
+		if err != nil {
+			return err
+		}
+		before := slices.Clone(lists)
+		if err := fn(&lists, l); err != nil {
+			return err
+		}
+		if slices.Equal(before, lists) {
+			return nil
+		}
+		e.Catalog = &catalogChange{Before: before, After: lists}
+		return c.save(lists)
+	})
+}
+
+func (c *Catalog) Create(name string) error {
+	if err := validListName(name); err != nil {
+		return err
+	}
+	return c.update("create list", func(lists *[]ListInfo, l *List) error {
+		if findList(*lists, name) >= 0 || slices.ContainsFunc(*l, func(t item) bool { return t.List == name }) {
+			return fmt.Errorf("%w: %q", ErrListExists, name)
+		}
+		*lists = append(*lists, ListInfo{Name: name})
+		return nil
+	})
+}
+
+// Rename renames list from to to, moving all its items.
+func (c *Catalog) Rename(from, to string) error {
+	if err := validListName(from); err != nil {
+		return err
+	}
+	if err := validListName(to); err != nil {
+		return err
+	}
+	return c.update("rename list", func(lists *[]ListInfo, l *List) error {
+		used := func(name string) bool {
+			return findList(*lists, name) >= 0 || slices.ContainsFunc(*l, func(t item) bool { return t.List == name })
+		}
+		if !used(from) {
+			return fmt.Errorf("%w: %q", ErrListNotFound, from)
+		}
+		if used(to) {
+			return fmt.Errorf("%w: %q", ErrListExists, to)
+		}
+		if i := findList(*lists, from); i >= 0 {
+			(*lists)[i].Name = to
+		}
+		for i := range *l {
+			if (*l)[i].List == from {
+				(*l)[i].List = to
+			}
+		}
+		return nil
+	})
+}
+
+// Archive hides list name from the combined view and stops new items
+// being added to it. Unarchive reverses it.
+func (c *Catalog) Archive(name string) error {
+	return c.setArchived(name, true)
+}
+
+func (c *Catalog) Unarchive(name string) error {
+	return c.setArchived(name, false)
+}
+
+func (c *Catalog) setArchived(name string, archived bool) error {
+	if err := validListName(name); err != nil {
+		return err
+	}
+	return c.update("archive list", func(lists *[]ListInfo, l *List) error {
+		i := findList(*lists, name)
+		if i < 0 {
+			if !slices.ContainsFunc(*l, func(t item) bool { return t.List == name }) {
+				return fmt.Errorf("%w: %q", ErrListNotFound, name)
+			}
+			*lists = append(*lists, ListInfo{Name: name})
+			i = len(*lists) - 1
+		}
+		(*lists)[i].Archived = archived
+		return nil
+	})
+}
+
+// Move moves item i to the named list, or to the default list for
+// DefaultList or "none".
+func (l *List) Move(i int, name string) error {
+	if err := l.check(i); err != nil {
+		return err
+	}
+	if listName(name) != "" {
+		if err := validListName(name); err != nil {
+			return err
+		}
+	}
+	(*l)[i-1].List = listName(name)
+	return nil
+}
@@ -0,0 +1,197 @@
+package todo_test
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestCatalog(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+	c := todo.NewCatalog(h)
+
+	err := h.Modify("add", func(l *todo.List) error {
+		l.Add("inbox task")
+		l.Add("ship release list:work")
+		l.Add("buy milk")
+		return l.Move(3, "home")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Create("someday"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create("work"); !errors.Is(err, todo.ErrListExists) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrListExists, err)
+	}
+	if err := c.Create("bad name"); !errors.Is(err, todo.ErrInvalidListName) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrInvalidListName, err)
+	}
+
+	if err := c.Rename("work", "job"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Rename("nope", "other"); !errors.Is(err, todo.ErrListNotFound) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrListNotFound, err)
+	}
+	if l := loadList(t, s); l[1].List != "job" {
+		t.Errorf("Expected item to move with renamed list, got %q", l[1].List)
+	}
+
+	if err := c.Archive("home"); err != nil {
+		t.Fatal(err)
+	}
+	archived, err := c.Archived()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(archived, []string{"home"}) {
+		t.Errorf("Expected archived lists %v, got %v instead", []string{"home"}, archived)
+	}
+
+	lists, err := c.Lists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []todo.ListInfo{
+		{Name: "default", Open: 1, Total: 1},
+		{Name: "home", Archived: true, Open: 1, Total: 1},
+		{Name: "job", Open: 1, Total: 1},
+		{Name: "someday"},
+	}
+	if !reflect.DeepEqual(lists, expected) {
+		t.Errorf("Expected %+v, got %+v instead", expected, lists)
+	}
+
+	if err := c.Unarchive("home"); err != nil {
+		t.Fatal(err)
+	}
+	if archived, _ := c.Archived(); len(archived) != 0 {
+		t.Errorf("Expected no archived lists, got %v", archived)
+	}
+}
+
+func TestCatalogUndo(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHistory(s, todo.DefaultHistoryDepth)
+	c := todo.NewCatalog(h)
+	err := h.Modify("add", func(l *todo.List) error {
+		l.Add("ship release list:work")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create("work2"); err != nil {
+		t.Fatal(err)
+	}
+	names := func() []string {
+		t.Helper()
+		lists, err := c.Lists()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, li := range lists {
+			names = append(names, li.Name)
+		}
+		return names
+	}
+
+	if err := c.Rename("work2", "home"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Rename("work", "job"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Archive("home"); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		op    string
+		names []string
+	}{
+		{"archive list", []string{"default", "home", "job"}},
+		{"rename list", []string{"default", "home", "work"}},
+		{"rename list", []string{"default", "work", "work2"}},
+		{"create list", []string{"default", "work"}},
+		{"add", []string{"default"}},
+	}
+	for _, step := range steps {
+		op, err := h.Undo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op != step.op {
+			t.Errorf("Expected to undo %q, got %q instead", step.op, op)
+		}
+		if got := names(); !slices.Equal(got, step.names) {
+			t.Errorf("After undoing %q expected lists %v, got %v instead", op, step.names, got)
+		}
+	}
+	if archived, _ := c.Archived(); len(archived) != 0 {
+		t.Errorf("Expected archiving to be undone, got %v", archived)
+	}
+
+	for range steps {
+		if _, err := h.Redo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := names(); !slices.Equal(got, []string{"default", "home", "job"}) {
+		t.Errorf("Expected lists to be redone, got %v instead", got)
+	}
+	if archived, _ := c.Archived(); !slices.Equal(archived, []string{"home"}) {
+		t.Errorf("Expected archiving to be redone, got %v", archived)
+	}
+}
+
+func TestQueryLists(t *testing.T) {
+	l := todo.List{}
+	l.Add("inbox task")
+	l.Add("ship release list:work")
+	l.Add("buy milk list:home")
+
+	testCases := []struct {
+		filter string
+		exp    []int
+	}{
+		{"list:work", []int{2}},
+		{"list:default", []int{1}},
+		{"-list:home", []int{1, 2}},
+		{"-list:home -list:work", []int{1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			q, err := todo.ParseQuery(tc.filter, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res := l.Select(q); !slices.Equal(res, tc.exp) {
+				t.Errorf("Expected %v, got %v instead", tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestMoveListName(t *testing.T) {
+	l := todo.List{}
+	l.Add("buy milk list:home")
+	if err := l.Move(1, "my list"); !errors.Is(err, todo.ErrInvalidListName) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrInvalidListName, err)
+	}
+	if l[0].List != "home" {
+		t.Errorf("Expected item to stay on %q, got %q instead", "home", l[0].List)
+	}
+	if err := l.Move(1, todo.DefaultList); err != nil || l[0].List != "" {
+		t.Errorf("Expected item on the default list, got %q, %v instead", l[0].List, err)
+	}
+}
@@ -0,0 +1,77 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockTimeout bounds how long exclusiveLock waits for another holder.
+var lockTimeout = 10 * time.Second
+
+// exclusiveLock locks by creating name exclusively, polling until a
+// previous holder removes it. The holder's PID is written to the file so
+// a lock left behind by a process that no longer exists is broken;
+// otherwise exclusiveLock gives up after lockTimeout.
+func exclusiveLock(name string) (func() error, error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(f, "%d\n", os.Getpid())
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				os.Remove(name)
+				return nil, err
+			}
+			return func() error { return os.Remove(name) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if staleLock(name) {
+			breakStaleLock(name)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %q", ErrLocked, name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// staleLock reports whether the process whose PID is recorded in name
+// is gone.
+func staleLock(name string) bool {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return err == nil && pid > 0 && !processExists(pid)
+}
+
+// breakStaleLock removes the stale lock name. Waiters take name.break
+// while checking it again, so that two of them can't both remove it and
+// the second one remove the lock the first one took meanwhile.
+func breakStaleLock(name string) {
+	guard := name + ".break"
+	f, err := os.OpenFile(guard, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		// Only a waiter that died while checking leaves the guard behind.
+		if fi, err := os.Stat(guard); err == nil && time.Since(fi.ModTime()) > lockTimeout {
+			os.Remove(guard)
+		}
+		return
+	}
+	f.Close()
+	defer os.Remove(guard)
+	if staleLock(name) {
+		os.Remove(name)
+	}
+}
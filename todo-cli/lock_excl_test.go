@@ -0,0 +1,89 @@
+package todo_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+// deadPID returns the PID of a process that has exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(exe, "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Process.Pid
+}
+
+func writeLock(t *testing.T, pid int) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), ".todo.json.lock")
+	if err := os.WriteFile(name, []byte(fmt.Sprintf("%d\n", pid)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestExclusiveLockStale(t *testing.T) {
+	name := writeLock(t, deadPID(t))
+	unlock, err := todo.ExclusiveLock(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := fmt.Sprintf("%d\n", os.Getpid()); string(data) != exp {
+		t.Errorf("Expected lock to hold %q, got %q instead", exp, data)
+	}
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(name); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected lock to be removed, got %v", err)
+	}
+}
+
+func TestExclusiveLockTimeout(t *testing.T) {
+	defer func(d time.Duration) { *todo.LockTimeout = d }(*todo.LockTimeout)
+	*todo.LockTimeout = 50 * time.Millisecond
+
+	name := writeLock(t, os.Getpid())
+	_, err := todo.ExclusiveLock(name)
+	if !errors.Is(err, todo.ErrLocked) || !strings.Contains(err.Error(), name) {
+		t.Errorf("Expected error %q naming %s, got %q instead", todo.ErrLocked, name, err)
+	}
+}
+
+func TestBreakStaleLockRechecks(t *testing.T) {
+	name := writeLock(t, deadPID(t))
+
+	// Another waiter breaks the stale lock and takes it before this one
+	// gets to act on having seen it stale.
+	unlock, err := todo.ExclusiveLock(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	todo.BreakStaleLock(name)
+
+	if _, err := os.Stat(name); err != nil {
+		t.Errorf("Expected the live lock to be kept, got %v", err)
+	}
+	if _, err := os.Stat(name + ".break"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the guard to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package todo
+
+import "os"
+
+// lockFile falls back to exclusiveLock on platforms without flock.
+func lockFile(name string) (func() error, error) {
+	return exclusiveLock(name)
+}
+
+// processExists reports whether a process with the given PID is running.
+func processExists(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,172 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Conflict is a change to an item that both sides of a merge made
+// differently. Ours and Theirs hold the JSON values of the field, or
+// "deleted" and "changed" when one side deleted an item the other one
+// changed.
+type Conflict struct {
+	ID     string
+	Field  string
+	Ours   string
+	Theirs string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s: ours %s, theirs %s", c.ID, c.Field, c.Ours, c.Theirs)
+}
+
+// Merge combines the changes ours and theirs made to base, matching items
+// by ID. Fields changed on one side take that side's value. Conflicting
+// changes keep ours, as do items one side deleted and the other changed,
+// and are returned along with the merged list. Items keep our order, with
+// items only they added following the item they came after.
+func Merge(base, ours, theirs List) (List, []Conflict) {
+	baseItems := items(base)
+	theirItems := items(theirs)
+	var (
+		merged    = List{}
+		conflicts []Conflict
+		seen      = map[string]bool{}
+	)
+
+	for _, o := range ours {
+		seen[o.ID] = true
+		b, inBase := baseItems[o.ID]
+		t, inTheirs := theirItems[o.ID]
+		switch {
+		case inTheirs:
+			m, cs := mergeItem(b, o, t)
+			merged = append(merged, m)
+			conflicts = append(conflicts, cs...)
+		case !inBase:
+			merged = append(merged, o)
+		case !sameItem(o, b):
+			merged = append(merged, o)
+			conflicts = append(conflicts, Conflict{ID: o.ID, Field: "item", Ours: "changed", Theirs: "deleted"})
+		}
+	}
+
+	prev := ""
+	for _, t := range theirs {
+		if seen[t.ID] {
+			prev = t.ID
+			continue
+		}
+		if b, inBase := baseItems[t.ID]; inBase {
+			if sameItem(t, b) {
+				continue
+			}
+			conflicts = append(conflicts, Conflict{ID: t.ID, Field: "item", Ours: "deleted", Theirs: "changed"})
+		}
+		merged = slices.Insert(merged, merged.indexOf(prev)+1, t)
+		prev = t.ID
+	}
+
+	for _, l := range []List{base, ours, theirs} {
+		for _, t := range l {
+			if merged.indexOf(t.ID) < 0 {
+				merged.unlink(t.ID)
+			}
+		}
+	}
+	return merged, conflicts
+}
+
+func items(l List) map[string]item {
+	m := make(map[string]item, len(l))
+	for _, t := range l {
+		m[t.ID] = t
+	}
+	return m
+}
+
+func jsonValue(v reflect.Value) string {
+	data, _ := json.Marshal(v.Interface())
+	return string(data)
+}
+
+func sameItem(a, b item) bool {
+	return jsonValue(reflect.ValueOf(a)) == jsonValue(reflect.ValueOf(b))
+}
+
+// mergeItem merges the fields of item o and t, both derived from b.
+func mergeItem(b, o, t item) (item, []Conflict) {
+	var conflicts []Conflict
+	m := o
+	mv := reflect.ValueOf(&m).Elem()
+	bv, tv := reflect.ValueOf(b), reflect.ValueOf(t)
+	for i := range mv.NumField() {
+		bj, oj, tj := jsonValue(bv.Field(i)), jsonValue(mv.Field(i)), jsonValue(tv.Field(i))
+		switch {
+		case oj == tj, tj == bj:
+		case oj == bj:
+			mv.Field(i).Set(tv.Field(i))
+		default:
+			name, _, _ := strings.Cut(mv.Type().Field(i).Tag.Get("json"), ",")
+			conflicts = append(conflicts, Conflict{ID: o.ID, Field: name, Ours: oj, Theirs: tj})
+		}
+	}
+	return m, conflicts
+}
+
+// readListFile reads a list written by List.Save or a JSONLStore and
+// reports whether it is in the JSON Lines format. A missing or empty
+// file is an empty list.
+func readListFile(filename string) (List, bool, error) {
+	data, err := readFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, false, nil
+	}
+	if data[0] == '[' {
+		var l List
+		if err := json.Unmarshal(data, &l); err != nil {
+			return nil, false, err
+		}
+		l.migrate()
+		return l, false, nil
+	}
+	l, err := ReadJSONL(bytes.NewReader(data))
+	return l, true, err
+}
+
+// MergeFiles merges the lists in the files base, ours and theirs as
+// Merge does and writes the result to ours, keeping its format. This
+// is the interface of a git merge driver.
+func MergeFiles(base, ours, theirs string) ([]Conflict, error) {
+	var lists [3]List
+	var lines bool
+	for i, name := range []string{base, ours, theirs} {
+		l, isLines, err := readListFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		lists[i] = l
+		if i == 1 {
+			lines = isLines
+		}
+	}
+
+	merged, conflicts := Merge(lists[0], lists[1], lists[2])
+	if lines {
+		return conflicts, NewJSONLStore(ours).Save(&merged)
+	}
+	return conflicts, merged.Save(ours)
+}
@@ -0,0 +1,168 @@
+package todo_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func jsonl(t *testing.T, lines ...string) todo.List {
+	t.Helper()
+	l, err := todo.ReadJSONL(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func tasks(l todo.List) []string {
+	var names []string
+	for _, t := range l {
+		names = append(names, t.Task)
+	}
+	return names
+}
+
+func TestMerge(t *testing.T) {
+	const (
+		a = `{"id":"aaaaaaaa","task":"call the vendor","done":false}`
+		b = `{"id":"bbbbbbbb","task":"water plants","done":false}`
+		c = `{"id":"cccccccc","task":"pay rent","done":false,"blocked_by":["aaaaaaaa"]}`
+	)
+	base := []string{a, b, c}
+
+	testCases := []struct {
+		name      string
+		ours      []string
+		theirs    []string
+		expTasks  []string
+		conflicts []string
+	}{
+		{name: "Unchanged", ours: base, theirs: base,
+			expTasks: []string{"call the vendor", "water plants", "pay rent"}},
+		{name: "DifferentItems",
+			ours:     []string{`{"id":"aaaaaaaa","task":"call the vendor today","done":false}`, b, c},
+			theirs:   []string{a, `{"id":"bbbbbbbb","task":"water plants","done":true}`, c},
+			expTasks: []string{"call the vendor today", "water plants", "pay rent"}},
+		{name: "DifferentFields",
+			ours:     []string{`{"id":"aaaaaaaa","task":"call the vendor today","done":false}`, b, c},
+			theirs:   []string{`{"id":"aaaaaaaa","task":"call the vendor","done":true}`, b, c},
+			expTasks: []string{"call the vendor today", "water plants", "pay rent"}},
+		{name: "BothAdded",
+			ours:     []string{a, `{"id":"dddddddd","task":"ours","done":false}`, b, c},
+			theirs:   []string{a, b, `{"id":"eeeeeeee","task":"theirs","done":false}`, c},
+			expTasks: []string{"call the vendor", "ours", "water plants", "theirs", "pay rent"}},
+		{name: "Deleted",
+			ours:     []string{b, c},
+			theirs:   []string{a, c},
+			expTasks: []string{"pay rent"}},
+		{name: "SameField",
+			ours:      []string{`{"id":"aaaaaaaa","task":"call the vendor today","done":false}`, b, c},
+			theirs:    []string{`{"id":"aaaaaaaa","task":"call the vendor tomorrow","done":false}`, b, c},
+			expTasks:  []string{"call the vendor today", "water plants", "pay rent"},
+			conflicts: []string{`aaaaaaaa task: ours "call the vendor today", theirs "call the vendor tomorrow"`}},
+		{name: "DeletedAndChanged",
+			ours:      []string{b, c},
+			theirs:    []string{`{"id":"aaaaaaaa","task":"call the vendor today","done":false}`, b, c},
+			expTasks:  []string{"call the vendor today", "water plants", "pay rent"},
+			conflicts: []string{"aaaaaaaa item: ours deleted, theirs changed"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, conflicts := todo.Merge(jsonl(t, base...), jsonl(t, tc.ours...), jsonl(t, tc.theirs...))
+			if got := tasks(merged); !reflect.DeepEqual(got, tc.expTasks) {
+				t.Errorf("Expected %q, got %q instead", tc.expTasks, got)
+			}
+			var got []string
+			for _, c := range conflicts {
+				got = append(got, c.String())
+			}
+			if !reflect.DeepEqual(got, tc.conflicts) {
+				t.Errorf("Expected conflicts %q, got %q instead", tc.conflicts, got)
+			}
+		})
+	}
+
+	t.Run("MergedFields", func(t *testing.T) {
+		ours := jsonl(t, `{"id":"aaaaaaaa","task":"call the vendor today","done":false}`, b, c)
+		theirs := jsonl(t, `{"id":"aaaaaaaa","task":"call the vendor","done":true}`, b, c)
+		merged, _ := todo.Merge(jsonl(t, base...), ours, theirs)
+		if merged[0].Task != "call the vendor today" || !merged[0].Done {
+			t.Errorf("Expected changes from both sides, got %+v", merged[0])
+		}
+	})
+
+	t.Run("UnlinksDeleted", func(t *testing.T) {
+		merged, _ := todo.Merge(jsonl(t, base...), jsonl(t, b, c), jsonl(t, base...))
+		if len(merged[1].BlockedBy) != 0 {
+			t.Errorf("Expected link to deleted item to be dropped, got %v", merged[1].BlockedBy)
+		}
+	})
+}
+
+func TestMergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base := write("base", `{"id":"aaaaaaaa","task":"call the vendor","done":false}`+"\n")
+	ours := write("ours", `{"id":"aaaaaaaa","task":"call the vendor","done":true}`+"\n")
+	theirs := write("theirs", `[{"id":"aaaaaaaa","task":"call the vendor","done":false},{"id":"bbbbbbbb","task":"water plants","done":false}]`)
+
+	conflicts, err := todo.MergeFiles(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %v", conflicts)
+	}
+
+	l := loadList(t, todo.NewJSONLStore(ours))
+	if len(l) != 2 || !l[0].Done || l[1].Task != "water plants" {
+		t.Errorf("Unexpected merge result: %+v", l)
+	}
+	data, err := os.ReadFile(ours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "\n") != 2 {
+		t.Errorf("Expected merge result to keep the JSON Lines format, got %q", data)
+	}
+}
+
+func TestMergeFilesLegacy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	// Lists saved before items had IDs.
+	base := write("base", `[{"task":"a","done":false},{"task":"b","done":false}]`)
+	ours := write("ours", `[{"task":"a","done":false},{"task":"b","done":false}]`)
+	theirs := write("theirs", `[{"task":"a","done":true},{"task":"b","done":false}]`)
+
+	conflicts, err := todo.MergeFiles(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %v", conflicts)
+	}
+
+	l := loadList(t, todo.NewJSONStore(ours))
+	if len(l) != 2 || !l[0].Done || l[0].ID == "" || l[1].ID == "" || l[0].ID == l[1].ID {
+		t.Errorf("Expected both items kept with distinct IDs and a completed, got %+v", l)
+	}
+}
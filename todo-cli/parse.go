@@ -0,0 +1,148 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+const dateLayout = "2006-01-02"
+
+var priorityNames = map[string]Priority{
+	"low":    PriorityLow,
+	"l":      PriorityLow,
+	"medium": PriorityMedium,
+	"med":    PriorityMedium,
+	"m":      PriorityMedium,
+	"high":   PriorityHigh,
+	"h":      PriorityHigh,
+}
+
+func ParsePriority(s string) (Priority, error) {
+	if s == "" || s == "none" {
+		return PriorityNone, nil
+	}
+	p, ok := priorityNames[strings.ToLower(s)]
+	if !ok {
+		return PriorityNone, fmt.Errorf("%w: %q", ErrInvalidPriority, s)
+	}
+	return p, nil
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	}
+	return "none"
+}
+
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Priority) UnmarshalText(b []byte) error {
+	v, err := ParsePriority(string(b))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+func ParseDate(s string) (time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	switch strings.ToLower(s) {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+	d, err := time.ParseInLocation(dateLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+	}
+	return d, nil
+}
+
+// parseTask pulls the inline +tag, @context, !priority, due:date,
+// rec:rule and list:name tokens out of text. Contexts are kept as tags
+// with their leading "@". Tokens that don't parse are kept as part of the
+// task text.
+func parseTask(text string) item {
+	var t item
+	var words []string
+	for _, w := range strings.Fields(text) {
+		switch {
+		case len(w) > 1 && (w[0] == '+' || w[0] == '@'):
+			tag := strings.TrimPrefix(w, "+")
+			if !slices.Contains(t.Tags, tag) {
+				t.Tags = append(t.Tags, tag)
+			}
+			continue
+		case len(w) > 1 && w[0] == '!':
+			if p, err := ParsePriority(w[1:]); err == nil {
+				t.Priority = p
+				continue
+			}
+		case strings.HasPrefix(w, "due:"):
+			if d, err := ParseDate(w[4:]); err == nil {
+				t.Due = d
+				continue
+			}
+		case strings.HasPrefix(w, "rec:"):
+			if r, err := ParseRecurrence(w[4:]); err == nil {
+				t.Recur = r.String()
+				continue
+			}
+		case strings.HasPrefix(w, "list:") && len(w) > 5:
+			t.List = listName(w[5:])
+			continue
+		}
+		words = append(words, w)
+	}
+	t.Task = strings.Join(words, " ")
+	return t
+}
+
+func (t item) label() string {
+	s := t.Task
+	if t.Priority != PriorityNone {
+		s += " !" + t.Priority.String()
+	}
+	if !t.Due.IsZero() {
+		s += " due:" + t.Due.Format(dateLayout)
+	}
+	if t.Recur != "" {
+		s += " rec:" + t.Recur
+	}
+	for _, tag := range t.Tags {
+		s += " " + tagToken(tag)
+	}
+	if t.List != "" {
+		s += " list:" + t.List
+	}
+	return s
+}
+
+func tagToken(tag string) string {
+	if strings.HasPrefix(tag, "@") {
+		return tag
+	}
+	return "+" + tag
+}
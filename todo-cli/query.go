@@ -0,0 +1,274 @@
+package todo
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Query selects and orders the items of a List. Filter expressions are a
+// space separated list of terms that must all match:
+//
+//	done:yes|no          completion state
+//	tag:name, +name      items tagged with name
+//	@context             items tagged with the todo.txt context
+//	pri<op>level, !level priority, e.g. pri:high or pri>=medium
+//	due<op>date          due date, e.g. due<2026-11-01 or due>=today
+//	due:none, due:any    items without/with a due date
+//	list:name            items in the named list, list:default for the
+//	                     default one
+//	text:word, word      case insensitive match on the task text
+//
+// where <op> is one of ":", "=", "<", "<=", ">" or ">=". A leading "-"
+// negates a term, e.g. -+work or -list:home. Sort keys are position,
+// created, completed, due, priority and task; a leading "-" reverses the
+// order.
+type Query struct {
+	filters []func(item) bool
+	sortBy  string
+	desc    bool
+}
+
+var sortKeys = map[string]func(a, b item) int{
+	"position": nil,
+	"created": func(a, b item) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	},
+	"completed": func(a, b item) int {
+		return a.CompletedAt.Compare(b.CompletedAt)
+	},
+	"due": func(a, b item) int {
+		if a.Due.IsZero() || b.Due.IsZero() {
+			return cmp.Compare(dueRank(a), dueRank(b))
+		}
+		return a.Due.Compare(b.Due)
+	},
+	"priority": func(a, b item) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	},
+	"task": func(a, b item) int {
+		return cmp.Compare(strings.ToLower(a.Task), strings.ToLower(b.Task))
+	},
+}
+
+func dueRank(t item) int {
+	if t.Due.IsZero() {
+		return 1
+	}
+	return 0
+}
+
+func ParseQuery(filter, sortBy string) (*Query, error) {
+	q := &Query{}
+	for _, term := range strings.Fields(filter) {
+		f, err := parseTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		q.filters = append(q.filters, f)
+	}
+
+	q.sortBy = strings.ToLower(sortBy)
+	if strings.HasPrefix(q.sortBy, "-") {
+		q.desc = true
+		q.sortBy = q.sortBy[1:]
+	}
+	if q.sortBy == "" {
+		q.sortBy = "position"
+	}
+	if _, ok := sortKeys[q.sortBy]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSortKey, sortBy)
+	}
+	return q, nil
+}
+
+func splitTerm(term string) (key, op, value string) {
+	i := strings.IndexAny(term, ":=<>")
+	if i <= 0 {
+		return "", "", term
+	}
+	key, rest := term[:i], term[i:]
+	for _, o := range []string{"<=", ">=", ":", "=", "<", ">"} {
+		if strings.HasPrefix(rest, o) {
+			return strings.ToLower(key), o, rest[len(o):]
+		}
+	}
+	return "", "", term
+}
+
+func compare(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return c == 0
+}
+
+func parseTerm(term string) (func(item) bool, error) {
+	if len(term) > 1 && term[0] == '-' {
+		f, err := parseTerm(term[1:])
+		if err != nil {
+			return nil, err
+		}
+		return func(t item) bool { return !f(t) }, nil
+	}
+
+	switch {
+	case len(term) > 1 && term[0] == '+':
+		term = "tag:" + term[1:]
+	case len(term) > 1 && term[0] == '@':
+		term = "tag:" + term
+	case len(term) > 1 && term[0] == '!':
+		term = "pri:" + term[1:]
+	}
+
+	key, op, value := splitTerm(term)
+	switch key {
+	case "":
+		return textFilter(value), nil
+	case "text":
+		return textFilter(value), nil
+	case "done":
+		done, err := parseYesNo(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidFilter, term)
+		}
+		return func(t item) bool { return t.Done == done }, nil
+	case "tag":
+		return func(t item) bool { return slices.Contains(t.Tags, value) }, nil
+	case "list":
+		name := listName(value)
+		return func(t item) bool { return t.List == name }, nil
+	case "pri", "priority":
+		p, err := ParsePriority(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(t item) bool { return compare(op, cmp.Compare(t.Priority, p)) }, nil
+	case "due":
+		switch value {
+		case "none":
+			return func(t item) bool { return t.Due.IsZero() }, nil
+		case "any":
+			return func(t item) bool { return !t.Due.IsZero() }, nil
+		}
+		d, err := ParseDate(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(t item) bool {
+			return !t.Due.IsZero() && compare(op, t.Due.Compare(d))
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidFilter, term)
+}
+
+func parseYesNo(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+func textFilter(word string) func(item) bool {
+	word = strings.ToLower(word)
+	return func(t item) bool {
+		return strings.Contains(strings.ToLower(t.Task), word)
+	}
+}
+
+func (q *Query) Match(t item) bool {
+	for _, f := range q.filters {
+		if !f(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the 1-based positions of the items matching q, ordered
+// by the query's sort key.
+func (l *List) Select(q *Query) []int {
+	var pos []int
+	for i, t := range *l {
+		if q.Match(t) {
+			pos = append(pos, i+1)
+		}
+	}
+
+	less := sortKeys[q.sortBy]
+	slices.SortStableFunc(pos, func(a, b int) int {
+		c := cmp.Compare(a, b)
+		if less != nil {
+			c = less((*l)[a-1], (*l)[b-1])
+		}
+		if q.desc {
+			return -c
+		}
+		return c
+	})
+	return pos
+}
+
+// Entry is an item together with its 1-based position in the list.
+type Entry struct {
+	Position int `json:"position"`
+	item
+}
+
+// Entries returns the items matching q with their positions, ordered by
+// the query's sort key.
+func (l *List) Entries(q *Query) []Entry {
+	pos := l.Select(q)
+	entries := make([]Entry, len(pos))
+	for k, i := range pos {
+		entries[k] = Entry{Position: i, item: (*l)[i-1]}
+	}
+	return entries
+}
+
+func (e Entry) String() string {
+	return e.line(false)
+}
+
+func (e Entry) line(ids bool) string {
+	prefix := " "
+	if e.Done {
+		prefix = "X "
+	}
+	id := ""
+	if ids {
+		id = " (" + e.ID + ")"
+	}
+	return fmt.Sprintf("%s%d%s: %s", prefix, e.Position, id, e.label())
+}
+
+// FormatEntries renders entries one per line the way View does,
+// including item IDs when ids is set.
+func FormatEntries(entries []Entry, ids bool) string {
+	formatted := ""
+	for _, e := range entries {
+		formatted += e.line(ids) + "\n"
+	}
+	return formatted
+}
+
+func (l *List) View(q *Query) string {
+	return FormatEntries(l.Entries(q), false)
+}
+
+// ViewIDs is like View but also shows each item's ID.
+func (l *List) ViewIDs(q *Query) string {
+	return FormatEntries(l.Entries(q), true)
+}
@@ -0,0 +1,78 @@
+package todo_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestQuery(t *testing.T) {
+	l := todo.List{}
+	l.Add("buy milk +home !low")
+	l.Add("ship release +work !high due:2026-11-01")
+	l.Add("write docs +work due:2026-10-20")
+	l.Add("renew passport !medium due:2027-01-15")
+	l.Complete(3)
+
+	testCases := []struct {
+		name   string
+		filter string
+		sort   string
+		exp    []int
+	}{
+		{"All", "", "", []int{1, 2, 3, 4}},
+		{"Open", "done:no", "", []int{1, 2, 4}},
+		{"Done", "done:yes", "", []int{3}},
+		{"Tag", "tag:work", "", []int{2, 3}},
+		{"TagShorthand", "+home", "", []int{1}},
+		{"Priority", "pri>=medium", "", []int{2, 4}},
+		{"PriorityShorthand", "!high", "", []int{2}},
+		{"DueBefore", "due<2026-12-01", "", []int{2, 3}},
+		{"DueAfter", "due>=2026-11-01", "", []int{2, 4}},
+		{"DueNone", "due:none", "", []int{1}},
+		{"Text", "DOCS", "", []int{3}},
+		{"Combined", "+work done:no", "", []int{2}},
+		{"SortDue", "", "due", []int{3, 2, 4, 1}},
+		{"SortPriority", "", "priority", []int{2, 4, 1, 3}},
+		{"SortTaskDesc", "", "-task", []int{3, 2, 4, 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := todo.ParseQuery(tc.filter, tc.sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := l.Select(q)
+			if !slices.Equal(res, tc.exp) {
+				t.Errorf("Expected %v, got %v instead", tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		filter string
+		sort   string
+		expErr error
+	}{
+		{"UnknownKey", "color:red", "", todo.ErrInvalidFilter},
+		{"BadDone", "done:maybe", "", todo.ErrInvalidFilter},
+		{"BadPriority", "pri:urgent", "", todo.ErrInvalidPriority},
+		{"BadDate", "due<someday", "", todo.ErrInvalidDate},
+		{"BadSort", "", "color", todo.ErrInvalidSortKey},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := todo.ParseQuery(tc.filter, tc.sort)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("Expected error %q, got %q instead", tc.expErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence is a rule for repeating an item. Rules are written as
+//
+//	daily                every day
+//	weekly               every week on the weekday of the due date
+//	weekly:mon,thu       every week on the given weekdays
+//	monthly              every month on the day of the due date
+//	monthly:N            every month on day N, or the last day of shorter months
+//	every:N              every N days
+type Recurrence struct {
+	kind     string
+	days     int
+	weekdays []time.Weekday
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func ParseRecurrence(s string) (Recurrence, error) {
+	kind, arg, _ := strings.Cut(strings.ToLower(s), ":")
+	r := Recurrence{kind: kind}
+	switch kind {
+	case "daily":
+		if arg == "" {
+			return r, nil
+		}
+	case "monthly":
+		if arg == "" {
+			return r, nil
+		}
+		if n, err := strconv.Atoi(arg); err == nil && n >= 1 && n <= 31 {
+			r.days = n
+			return r, nil
+		}
+	case "weekly":
+		if arg == "" {
+			return r, nil
+		}
+		for _, d := range strings.Split(arg, ",") {
+			wd, ok := weekdayNames[d]
+			if !ok {
+				return Recurrence{}, fmt.Errorf("%w: unknown weekday %q", ErrInvalidRecurrence, d)
+			}
+			if !slices.Contains(r.weekdays, wd) {
+				r.weekdays = append(r.weekdays, wd)
+			}
+		}
+		slices.Sort(r.weekdays)
+		return r, nil
+	case "every":
+		n, err := strconv.Atoi(strings.TrimSuffix(arg, "d"))
+		if err == nil && n > 0 {
+			r.days = n
+			return r, nil
+		}
+	}
+	return Recurrence{}, fmt.Errorf("%w: %q", ErrInvalidRecurrence, s)
+}
+
+func (r Recurrence) String() string {
+	switch {
+	case r.kind == "every":
+		return fmt.Sprintf("every:%d", r.days)
+	case r.kind == "monthly" && r.days > 0:
+		return fmt.Sprintf("monthly:%d", r.days)
+	case r.kind == "weekly" && len(r.weekdays) > 0:
+		names := make([]string, len(r.weekdays))
+		for i, wd := range r.weekdays {
+			names[i] = strings.ToLower(wd.String()[:3])
+		}
+		return "weekly:" + strings.Join(names, ",")
+	}
+	return r.kind
+}
+
+// Next returns the first occurrence after from.
+func (r Recurrence) Next(from time.Time) time.Time {
+	switch r.kind {
+	case "daily":
+		return from.AddDate(0, 0, 1)
+	case "every":
+		return from.AddDate(0, 0, r.days)
+	case "monthly":
+		day := r.days
+		if day == 0 {
+			day = from.Day()
+		}
+		return addMonth(from, day)
+	case "weekly":
+		if len(r.weekdays) == 0 {
+			return from.AddDate(0, 0, 7)
+		}
+		for d := 1; ; d++ {
+			next := from.AddDate(0, 0, d)
+			if slices.Contains(r.weekdays, next.Weekday()) {
+				return next
+			}
+		}
+	}
+	return from
+}
+
+// addMonth moves t to day of the following month, clamping to the last
+// day of the month so that recurrences on the 31st don't skip short
+// months.
+func addMonth(t time.Time, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// nextRule returns the rule of the occurrence following the completed
+// item t and the date it counts from: t's due date, or its completion
+// day when it has none. A monthly rule from the 29th on is anchored to
+// that day, so a short month doesn't move the occurrences after it.
+func (t item) nextRule() (Recurrence, time.Time, error) {
+	r, err := ParseRecurrence(t.Recur)
+	if err != nil {
+		return r, time.Time{}, err
+	}
+	base := t.Due
+	if base.IsZero() {
+		y, m, d := t.CompletedAt.Date()
+		base = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	}
+	if r.kind == "monthly" && r.days == 0 && base.Day() > 28 {
+		r.days = base.Day()
+	}
+	return r, base, nil
+}
+
+// recur appends the occurrence following the completed recurring item t.
+// The next due date counts from t's due date, or from its completion when
+// it has none, and is moved past today if t was completed late.
+func (l *List) recur(t item) {
+	r, base, err := t.nextRule()
+	if err != nil {
+		return
+	}
+
+	y, m, d := t.CompletedAt.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	next := r.Next(base)
+	for !next.After(today) {
+		next = r.Next(next)
+	}
+
+	n := item{
+		ID:        l.newID(),
+		Task:      t.Task,
+		CreatedAt: t.CompletedAt,
+		Priority:  t.Priority,
+		Due:       next,
+		Tags:      slices.Clone(t.Tags),
+		Parent:    t.Parent,
+		Recur:     r.String(),
+		List:      t.List,
+	}
+	*l = append(*l, n)
+}
@@ -0,0 +1,145 @@
+package todo_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	testCases := []struct {
+		rule string
+		from time.Time
+		exp  time.Time
+	}{
+		{"daily", date(2026, 10, 18), date(2026, 10, 19)},
+		{"weekly", date(2026, 10, 18), date(2026, 10, 25)},
+		{"weekly:mon,thu", date(2026, 10, 18), date(2026, 10, 19)},
+		{"weekly:mon,thu", date(2026, 10, 19), date(2026, 10, 22)},
+		{"weekly:mon,thu", date(2026, 10, 22), date(2026, 10, 26)},
+		{"monthly", date(2026, 10, 18), date(2026, 11, 18)},
+		{"monthly", date(2026, 1, 31), date(2026, 2, 28)},
+		{"monthly:31", date(2026, 2, 28), date(2026, 3, 31)},
+		{"monthly:30", date(2026, 1, 30), date(2026, 2, 28)},
+		{"every:3", date(2026, 10, 18), date(2026, 10, 21)},
+		{"every:10d", date(2026, 12, 28), date(2027, 1, 7)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rule, func(t *testing.T) {
+			r, err := todo.ParseRecurrence(tc.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res := r.Next(tc.from); !res.Equal(tc.exp) {
+				t.Errorf("Expected %s, got %s instead", tc.exp, res)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	for _, rule := range []string{"hourly", "weekly:funday", "every:0", "every:x", "daily:2", "monthly:32", "monthly:0"} {
+		t.Run(rule, func(t *testing.T) {
+			if _, err := todo.ParseRecurrence(rule); !errors.Is(err, todo.ErrInvalidRecurrence) {
+				t.Errorf("Expected error %q, got %q instead", todo.ErrInvalidRecurrence, err)
+			}
+		})
+	}
+}
+
+func TestCompleteRecurring(t *testing.T) {
+	l := todo.List{}
+	next := time.Now().AddDate(0, 0, 3)
+	due := date(next.Year(), next.Month(), next.Day())
+	l.Add("rotate on-call +ops rec:weekly due:" + due.Format("2006-01-02"))
+
+	if l[0].Task != "rotate on-call" || l[0].Recur != "weekly" {
+		t.Fatalf("Unexpected recurring item: %+v", l[0])
+	}
+	if err := l.Complete(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 2 {
+		t.Fatalf("Expected next occurrence to be added, got %d items", len(l))
+	}
+	if !l[0].Done || l[0].CompletedAt.IsZero() {
+		t.Errorf("Expected completion to be recorded, got %+v", l[0])
+	}
+	n := l[1]
+	if n.Done || n.Task != l[0].Task || n.Recur != "weekly" || n.Tags[0] != "ops" || n.ID == l[0].ID {
+		t.Errorf("Unexpected next occurrence: %+v", n)
+	}
+	if exp := due.AddDate(0, 0, 7); !n.Due.Equal(exp) {
+		t.Errorf("Expected next due date %s, got %s instead", exp, n.Due)
+	}
+}
+
+func TestCompleteMonthlyShortMonth(t *testing.T) {
+	l := todo.List{}
+	due := date(time.Now().Year()+1, 1, 31)
+	l.Add("pay rent rec:monthly due:" + due.Format("2006-01-02"))
+
+	// Day 0 of March is the last day of February.
+	expected := []time.Time{date(due.Year(), 3, 0), date(due.Year(), 3, 31), date(due.Year(), 4, 30)}
+	for k, exp := range expected {
+		if err := l.Complete(len(l)); err != nil {
+			t.Fatal(err)
+		}
+		if n := l[len(l)-1]; !n.Due.Equal(exp) || n.Recur != "monthly:31" {
+			t.Errorf("Step %d: expected due %s on monthly:31, got %s on %s instead", k+1, exp, n.Due, n.Recur)
+		}
+	}
+
+	if err := l.Reopen(len(l) - 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 3 {
+		t.Errorf("Expected reopening to remove the anchored occurrence, got %d items", len(l))
+	}
+}
+
+func TestCompleteRecurringTwice(t *testing.T) {
+	l := todo.List{}
+	l.Add("water plants rec:daily")
+	if err := l.Complete(1); err != nil {
+		t.Fatal(err)
+	}
+	completed := l[0].CompletedAt
+	for range 2 {
+		if err := l.Complete(1); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.ForceComplete(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(l) != 2 {
+		t.Errorf("Expected a single next occurrence, got %d items", len(l))
+	}
+	if !l[0].CompletedAt.Equal(completed) {
+		t.Errorf("Expected completion time %s to be kept, got %s instead", completed, l[0].CompletedAt)
+	}
+}
+
+func TestCompleteRecurringLate(t *testing.T) {
+	l := todo.List{}
+	l.Add("water plants rec:every:2 due:2020-01-01")
+	l.Complete(1)
+
+	now := time.Now()
+	today := date(now.Year(), now.Month(), now.Day())
+	n := l[1]
+	if !n.Due.After(today) || n.Due.After(today.AddDate(0, 0, 2)) {
+		t.Errorf("Expected next due date within two days after %s, got %s", today, n.Due)
+	}
+	if days := int(n.Due.Sub(date(2020, 1, 1)).Hours()/24+0.5) % 2; days != 0 {
+		t.Errorf("Expected next due date to stay on the every:2 schedule, got %s", n.Due)
+	}
+}
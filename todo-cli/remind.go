@@ -0,0 +1,99 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Reminder is sent to the sinks of a Watcher for an item that is due.
+type Reminder struct {
+	Entry
+	Message string `json:"message"`
+}
+
+// Snooze holds back reminders for item i until the given time. A zero
+// time clears the snooze.
+func (l *List) Snooze(i int, until time.Time) error {
+	ls := *l
+	if i <= 0 || i > len(ls) {
+		return fmt.Errorf("Item %d does not exist", i)
+	}
+	ls[i-1].SnoozedUntil = until
+	return nil
+}
+
+// ParseSnooze parses how long to snooze for, either a duration such as
+// 30m or 2h, or a date as accepted by ParseDate.
+func ParseSnooze(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+	return ParseDate(s)
+}
+
+// Watcher sends reminders for the open items of a store that are due.
+// The reminders already sent are only remembered in memory, so a new
+// Watcher, e.g. after restarting todo watch, sends them again.
+type Watcher struct {
+	store Store
+	sinks []Sink
+	// Lead is how long before the start of its due day an item is due.
+	Lead time.Duration
+	// Repeat is how often reminders are repeated while an item stays
+	// due. With zero each item is reminded of once per due date, and
+	// again when a snooze runs out.
+	Repeat time.Duration
+	sent   map[string]time.Time
+}
+
+func NewWatcher(s Store, sinks ...Sink) *Watcher {
+	return &Watcher{store: s, sinks: sinks, sent: map[string]time.Time{}}
+}
+
+// Check sends the reminders due at now. An item counts as reminded of
+// once any sink accepted it; errors from the sinks are returned joined.
+func (w *Watcher) Check(now time.Time) error {
+	l := &List{}
+	if err := w.store.Load(l); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, t := range *l {
+		if t.Done || t.Due.IsZero() || now.Before(t.Due.Add(-w.Lead)) || now.Before(t.SnoozedUntil) {
+			continue
+		}
+		key := t.ID + " " + t.Due.Format(dateLayout)
+		last, ok := w.sent[key]
+		if ok && !last.Before(t.SnoozedUntil) && (w.Repeat <= 0 || now.Sub(last) < w.Repeat) {
+			continue
+		}
+
+		r := Reminder{Entry: Entry{Position: i + 1, item: t}, Message: reminderMessage(i+1, t, now)}
+		sent := false
+		for _, s := range w.sinks {
+			if err := s.Notify(r); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			sent = true
+		}
+		if sent {
+			w.sent[key] = now
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func reminderMessage(pos int, t item, now time.Time) string {
+	task := fmt.Sprintf("Task %d %q", pos, t.Task)
+	today := startOfDay(now)
+	switch {
+	case t.Due.Before(today):
+		return fmt.Sprintf("%s is overdue since %s", task, t.Due.Format(dateLayout))
+	case t.Due.Equal(today):
+		return task + " is due today"
+	}
+	return fmt.Sprintf("%s is due on %s", task, t.Due.Format(dateLayout))
+}
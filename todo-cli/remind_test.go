@@ -0,0 +1,106 @@
+package todo_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+type recordSink struct {
+	messages []string
+}
+
+func (s *recordSink) Notify(r todo.Reminder) error {
+	s.messages = append(s.messages, r.Message)
+	return nil
+}
+
+func TestWatcher(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	err := todo.Modify(s, func(l *todo.List) error {
+		l.Add("pay rent due:2026-10-01")
+		l.Add("call the vendor due:2026-10-18")
+		l.Add("water plants due:2026-10-20")
+		l.Add("no due date")
+		l.Add("done already due:2026-10-01")
+		return l.Complete(5)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &recordSink{}
+	w := todo.NewWatcher(s, sink)
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+
+	check := func(now time.Time, exp ...string) {
+		t.Helper()
+		sink.messages = nil
+		if err := w.Check(now); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sink.messages, exp) {
+			t.Errorf("Expected %q, got %q instead", exp, sink.messages)
+		}
+	}
+
+	t.Run("Due", func(t *testing.T) {
+		check(now,
+			`Task 1 "pay rent" is overdue since 2026-10-01`,
+			`Task 2 "call the vendor" is due today`)
+	})
+	t.Run("Once", func(t *testing.T) {
+		check(now.Add(time.Hour))
+	})
+	t.Run("Lead", func(t *testing.T) {
+		w.Lead = 48 * time.Hour
+		check(now.Add(time.Hour), `Task 3 "water plants" is due on 2026-10-20`)
+	})
+	t.Run("Repeat", func(t *testing.T) {
+		w.Repeat = 2 * time.Hour
+		check(now.Add(90 * time.Minute))
+		check(now.Add(2*time.Hour),
+			`Task 1 "pay rent" is overdue since 2026-10-01`,
+			`Task 2 "call the vendor" is due today`)
+		w.Repeat = 0
+	})
+	t.Run("Snooze", func(t *testing.T) {
+		err := todo.Modify(s, func(l *todo.List) error {
+			return l.Snooze(2, now.Add(4*time.Hour))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(now.Add(3 * time.Hour))
+		check(now.Add(4*time.Hour), `Task 2 "call the vendor" is due today`)
+		check(now.Add(5 * time.Hour))
+	})
+}
+
+func TestParseSnooze(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	testCases := []struct {
+		in  string
+		exp time.Time
+	}{
+		{"90m", now.Add(90 * time.Minute)},
+		{"2026-10-21", time.Date(2026, 10, 21, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := todo.ParseSnooze(tc.in, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tc.exp) {
+				t.Errorf("Expected %s, got %s instead", tc.exp, got)
+			}
+		})
+	}
+	if _, err := todo.ParseSnooze("later", now); err == nil {
+		t.Error("Expected invalid snooze to fail")
+	}
+}
@@ -0,0 +1,174 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Stats summarizes a list as of a point in time.
+type Stats struct {
+	Now       time.Time
+	Total     int
+	Open      int
+	Done      int
+	Overdue   int
+	AvgToDone time.Duration
+	// PerDay and PerWeek count completions for each of the last days and
+	// weeks, oldest first, ending with the current day or week.
+	PerDay  []int
+	PerWeek []int
+	// Oldest holds the positions of the oldest open items, oldest first.
+	Oldest []int
+	// Burndown holds the number of open items at the end of each of the
+	// last days, oldest first, ending today.
+	Burndown []int
+}
+
+// Stats computes statistics for l covering the given number of days.
+func (l *List) Stats(now time.Time, days int) Stats {
+	days = max(days, 1)
+	s := Stats{
+		Now:      now,
+		Total:    len(*l),
+		PerDay:   make([]int, days),
+		PerWeek:  make([]int, (days+6)/7),
+		Burndown: make([]int, days),
+	}
+	today := startOfDay(now)
+
+	var toDone time.Duration
+	var open []int
+	for i, t := range *l {
+		if !t.Done {
+			s.Open++
+			open = append(open, i+1)
+			if !t.Due.IsZero() && t.Due.Before(today) {
+				s.Overdue++
+			}
+		} else {
+			s.Done++
+			if !t.CreatedAt.IsZero() && !t.CompletedAt.IsZero() {
+				toDone += t.CompletedAt.Sub(t.CreatedAt)
+			}
+			age := int(today.Sub(startOfDay(t.CompletedAt)).Hours()/24 + 0.5)
+			if age >= 0 && age < days {
+				s.PerDay[days-1-age]++
+			}
+			if w := age / 7; age >= 0 && w < len(s.PerWeek) {
+				s.PerWeek[len(s.PerWeek)-1-w]++
+			}
+		}
+
+		for d := range days {
+			end := today.AddDate(0, 0, d-days+2)
+			if t.CreatedAt.Before(end) && (!t.Done || !t.CompletedAt.Before(end)) {
+				s.Burndown[d]++
+			}
+		}
+	}
+	if s.Done > 0 {
+		s.AvgToDone = toDone / time.Duration(s.Done)
+	}
+
+	slices.SortStableFunc(open, func(a, b int) int {
+		return (*l)[a-1].CreatedAt.Compare((*l)[b-1].CreatedAt)
+	})
+	s.Oldest = open[:min(len(open), 5)]
+	return s
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// Report renders s as a text summary, followed by an ASCII burndown chart
+// when chart is set. l is used to show the oldest open items.
+func (l *List) Report(s Stats, chart bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tasks: %d total, %d open, %d done, %d overdue\n", s.Total, s.Open, s.Done, s.Overdue)
+	if s.Done > 0 {
+		fmt.Fprintf(&b, "Average time to complete: %s\n", formatDuration(s.AvgToDone))
+	}
+
+	days := len(s.PerDay)
+	if days > 0 {
+		total := 0
+		for _, n := range s.PerDay {
+			total += n
+		}
+		fmt.Fprintf(&b, "Completed in the last %d days: %d (%.1f per day)\n", days, total, float64(total)/float64(days))
+		fmt.Fprintf(&b, "Per day:  %s\n", joinInts(s.PerDay))
+		fmt.Fprintf(&b, "Per week: %s\n", joinInts(s.PerWeek))
+	}
+
+	if len(s.Oldest) > 0 {
+		b.WriteString("Oldest open tasks:\n")
+		for _, i := range s.Oldest {
+			t := (*l)[i-1]
+			fmt.Fprintf(&b, "  %d: %s (%s old)\n", i, t.label(), formatAge(t.CreatedAt, s.Now))
+		}
+	}
+
+	if chart && days > 0 {
+		b.WriteString("Burndown (open tasks per day):\n")
+		b.WriteString(burndown(s.Burndown, s.Now))
+	}
+	return b.String()
+}
+
+func joinInts(n []int) string {
+	s := make([]string, len(n))
+	for i, v := range n {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, " ")
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%.1f days", d.Hours()/24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%.1f hours", d.Hours())
+	}
+	return fmt.Sprintf("%.0f minutes", d.Minutes())
+}
+
+const chartHeight = 8
+
+// burndown draws counts as a bar chart, one column per day ending today.
+func burndown(counts []int, now time.Time) string {
+	peak := slices.Max(counts)
+	if peak == 0 {
+		peak = 1
+	}
+	width := len(fmt.Sprint(peak))
+
+	var b strings.Builder
+	for row := chartHeight; row > 0; row-- {
+		label := ""
+		if row == chartHeight {
+			label = fmt.Sprint(peak)
+		}
+		fmt.Fprintf(&b, "%*s |", width, label)
+		for _, n := range counts {
+			height := (n*chartHeight + peak/2) / peak
+			if n > 0 && height == 0 {
+				height = 1
+			}
+			if height >= row {
+				b.WriteString("#")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%*s +%s\n", width, "0", strings.Repeat("-", len(counts)))
+	start := startOfDay(now).AddDate(0, 0, 1-len(counts))
+	fmt.Fprintf(&b, "%*s  %s .. %s\n", width, "", start.Format(dateLayout), startOfDay(now).Format(dateLayout))
+	return b.String()
+}
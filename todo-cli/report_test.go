@@ -0,0 +1,73 @@
+package todo_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func reportList() (todo.List, time.Time) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.Local)
+	day := func(d, h int) time.Time { return time.Date(2026, 10, d, h, 0, 0, 0, time.Local) }
+
+	l := todo.List{}
+	for _, task := range []string{"a", "b", "c", "d", "e"} {
+		l.Add(task)
+	}
+	l[0].CreatedAt, l[0].Done, l[0].CompletedAt = day(10, 9), true, day(12, 9)
+	l[1].CreatedAt, l[1].Done, l[1].CompletedAt = day(11, 9), true, day(18, 9)
+	l[2].CreatedAt, l[2].Done, l[2].CompletedAt = day(17, 9), true, day(18, 9)
+	l[3].CreatedAt, l[3].Due = day(5, 9), day(15, 0)
+	l[4].CreatedAt = day(16, 9)
+	return l, now
+}
+
+func TestStats(t *testing.T) {
+	l, now := reportList()
+	s := l.Stats(now, 7)
+
+	if s.Total != 5 || s.Open != 2 || s.Done != 3 || s.Overdue != 1 {
+		t.Errorf("Unexpected counts: %+v", s)
+	}
+	if exp := (48*time.Hour + 168*time.Hour + 24*time.Hour) / 3; s.AvgToDone != exp {
+		t.Errorf("Expected average time to complete %s, got %s instead", exp, s.AvgToDone)
+	}
+	if exp := []int{1, 0, 0, 0, 0, 0, 2}; !slices.Equal(s.PerDay, exp) {
+		t.Errorf("Expected per day %v, got %v instead", exp, s.PerDay)
+	}
+	if exp := []int{3}; !slices.Equal(s.PerWeek, exp) {
+		t.Errorf("Expected per week %v, got %v instead", exp, s.PerWeek)
+	}
+	if exp := []int{4, 5}; !slices.Equal(s.Oldest, exp) {
+		t.Errorf("Expected oldest %v, got %v instead", exp, s.Oldest)
+	}
+	if exp := []int{2, 2, 2, 2, 3, 4, 2}; !slices.Equal(s.Burndown, exp) {
+		t.Errorf("Expected burndown %v, got %v instead", exp, s.Burndown)
+	}
+}
+
+func TestReport(t *testing.T) {
+	l, now := reportList()
+	res := l.Report(l.Stats(now, 7), true)
+
+	for _, exp := range []string{
+		"Tasks: 5 total, 2 open, 3 done, 1 overdue\n",
+		"Average time to complete: 3.3 days\n",
+		"Completed in the last 7 days: 3 (0.4 per day)\n",
+		"Per day:  1 0 0 0 0 0 2\n",
+		"  4: d due:2026-10-15 (13d old)\n",
+		"4 |     # \n",
+		"0 +-------\n",
+		"   2026-10-12 .. 2026-10-18\n",
+	} {
+		if !strings.Contains(res, exp) {
+			t.Errorf("Expected report to contain %q, got:\n%s", exp, res)
+		}
+	}
+	if strings.Contains(l.Report(l.Stats(now, 7), false), "Burndown") {
+		t.Error("Expected no chart when not requested")
+	}
+}
@@ -0,0 +1,221 @@
+package todo
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// NewHandler returns an http.Handler exposing the list kept by h as a
+// JSON REST API:
+//
+//	GET    /todos?filter=...&sort=...  list matching items
+//	POST   /todos                      add {"task": "...", "parent": "ref"}
+//	GET    /todos/{ref}                get one item
+//	PATCH  /todos/{ref}                edit {"changes": "..."}, see ParseChanges
+//	POST   /todos/{ref}/complete       complete an item, ?force=true to force
+//	DELETE /todos/{ref}                delete an item
+//
+// where ref is an item position or ID, as accepted by List.Lookup.
+func NewHandler(h *History) http.Handler {
+	s := &server{history: h}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /todos", s.list)
+	mux.HandleFunc("POST /todos", s.add)
+	mux.HandleFunc("GET /todos/{ref}", s.get)
+	mux.HandleFunc("PATCH /todos/{ref}", s.update)
+	mux.HandleFunc("POST /todos/{ref}/complete", s.complete)
+	mux.HandleFunc("DELETE /todos/{ref}", s.delete)
+	return mux
+}
+
+// RequireToken wraps h so that every request must carry token in an
+// "Authorization: Bearer" header.
+func RequireToken(token string, h http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			replyError(w, ErrUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+type server struct {
+	history *History
+}
+
+type addRequest struct {
+	Task   string `json:"task"`
+	Parent string `json:"parent,omitempty"`
+}
+
+type updateRequest struct {
+	Changes string `json:"changes"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func replyJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func replyError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, ErrAmbiguousRef), errors.Is(err, ErrInvalidFilter),
+		errors.Is(err, ErrInvalidSortKey), errors.Is(err, ErrInvalidPriority),
+		errors.Is(err, ErrInvalidDate), errors.Is(err, ErrInvalidRecurrence),
+		errors.Is(err, ErrInvalidRequest), errors.Is(err, ErrEmptyTask):
+		status = http.StatusBadRequest
+	case errors.Is(err, ErrOpenSubtasks), errors.Is(err, ErrBlocked), errors.Is(err, ErrCycle):
+		status = http.StatusConflict
+	}
+	replyJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func (s *server) load() (*List, error) {
+	l := &List{}
+	return l, s.history.store.Load(l)
+}
+
+func (s *server) list(w http.ResponseWriter, r *http.Request) {
+	q, err := ParseQuery(r.URL.Query().Get("filter"), r.URL.Query().Get("sort"))
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	l, err := s.load()
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	replyJSON(w, http.StatusOK, l.Entries(q))
+}
+
+func (s *server) get(w http.ResponseWriter, r *http.Request) {
+	l, err := s.load()
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	i, err := l.Lookup(r.PathValue("ref"))
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	replyJSON(w, http.StatusOK, Entry{Position: i, item: (*l)[i-1]})
+}
+
+func (s *server) add(w http.ResponseWriter, r *http.Request) {
+	var req addRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Task == "" {
+		replyError(w, ErrInvalidRequest)
+		return
+	}
+
+	var e Entry
+	err := s.history.Modify("add", func(l *List) error {
+		p := 0
+		if req.Parent != "" {
+			var err error
+			if p, err = l.Lookup(req.Parent); err != nil {
+				return err
+			}
+		}
+		l.Add(req.Task)
+		if p > 0 {
+			if err := l.SetParent(len(*l), p); err != nil {
+				return err
+			}
+		}
+		e = Entry{Position: len(*l), item: (*l)[len(*l)-1]}
+		return nil
+	})
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	replyJSON(w, http.StatusCreated, e)
+}
+
+func (s *server) update(w http.ResponseWriter, r *http.Request) {
+	var req updateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Changes == "" {
+		replyError(w, ErrInvalidRequest)
+		return
+	}
+
+	changes, err := ParseChanges(req.Changes)
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+
+	var e Entry
+	err = s.history.Modify("edit", func(l *List) error {
+		i, err := l.Lookup(r.PathValue("ref"))
+		if err != nil {
+			return err
+		}
+		if err := l.Update(i, changes); err != nil {
+			return err
+		}
+		e = Entry{Position: i, item: (*l)[i-1]}
+		return nil
+	})
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	replyJSON(w, http.StatusOK, e)
+}
+
+func (s *server) complete(w http.ResponseWriter, r *http.Request) {
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	var e Entry
+	err := s.history.Modify("complete", func(l *List) error {
+		i, err := l.Lookup(r.PathValue("ref"))
+		if err != nil {
+			return err
+		}
+		if force {
+			err = l.ForceComplete(i)
+		} else {
+			err = l.Complete(i)
+		}
+		e = Entry{Position: i, item: (*l)[i-1]}
+		return err
+	})
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	replyJSON(w, http.StatusOK, e)
+}
+
+func (s *server) delete(w http.ResponseWriter, r *http.Request) {
+	err := s.history.Modify("delete", func(l *List) error {
+		i, err := l.Lookup(r.PathValue("ref"))
+		if err != nil {
+			return err
+		}
+		return l.Delete(i)
+	})
+	if err != nil {
+		replyError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
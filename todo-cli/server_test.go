@@ -0,0 +1,159 @@
+package todo_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func setupServer(t *testing.T) (*todo.Client, todo.Store) {
+	t.Helper()
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	ts := httptest.NewServer(todo.NewHandler(todo.NewHistory(s, todo.DefaultHistoryDepth)))
+	t.Cleanup(ts.Close)
+	return todo.NewClient(ts.URL), s
+}
+
+func TestServerClient(t *testing.T) {
+	c, s := setupServer(t)
+
+	e, err := c.Add("ship release +work !high", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Position != 1 || e.Task != "ship release" || e.Priority != todo.PriorityHigh || e.ID == "" {
+		t.Errorf("Unexpected added entry: %+v", e)
+	}
+	if _, err := c.Add("write notes", e.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Add("buy milk +home", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := c.List("+work", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != e.ID {
+		t.Errorf("Expected only %q to match, got %+v", e.ID, entries)
+	}
+
+	if _, err := c.Complete(e.ID, false); !errors.Is(err, todo.ErrOpenSubtasks) {
+		t.Errorf("Expected error %q completing parent with open subtask, got %q", todo.ErrOpenSubtasks, err)
+	}
+	tree, err := c.Tree("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedTree := " 1: ship release !high +work\n   2: write notes\n 3: buy milk +home\n"
+	if tree != expectedTree {
+		t.Errorf("Expected %q, got %q instead", expectedTree, tree)
+	}
+	if _, err := c.Complete(e.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete("3"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.Get(e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Done {
+		t.Errorf("Expected %q to be completed", e.ID)
+	}
+
+	l := loadList(t, s)
+	if len(l) != 2 || !l[0].Done || l[1].Parent != e.ID {
+		t.Errorf("Unexpected list saved by the server: %+v", l)
+	}
+
+	entries, err = c.List("", "-position")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := " 2: write notes\nX 1: ship release !high +work\n"
+	if res := todo.FormatEntries(entries, false); res != expected {
+		t.Errorf("Expected %q, got %q instead", expected, res)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	c, _ := setupServer(t)
+	if _, err := c.Add("only task", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get("7"); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNotFound, err)
+	}
+	if err := c.Delete("nosuchid"); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNotFound, err)
+	}
+	if _, err := c.List("color:red", ""); !errors.Is(err, todo.ErrRemote) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrRemote, err)
+	}
+}
+
+func TestServerToken(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHandler(todo.NewHistory(s, todo.DefaultHistoryDepth))
+	ts := httptest.NewServer(todo.RequireToken("secret", h))
+	t.Cleanup(ts.Close)
+
+	c := todo.NewClient(ts.URL)
+	if _, err := c.Add("task", ""); !errors.Is(err, todo.ErrUnauthorized) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrUnauthorized, err)
+	}
+	c.Token = "wrong"
+	if _, err := c.List("", ""); !errors.Is(err, todo.ErrUnauthorized) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrUnauthorized, err)
+	}
+	c.Token = "secret"
+	if _, err := c.Add("task", ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerStatusCodes(t *testing.T) {
+	s := todo.NewJSONStore(filepath.Join(t.TempDir(), ".todo.json"))
+	h := todo.NewHandler(todo.NewHistory(s, todo.DefaultHistoryDepth))
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		exp    int
+	}{
+		{"Add", http.MethodPost, "/todos", `{"task":"first"}`, http.StatusCreated},
+		{"AddEmpty", http.MethodPost, "/todos", `{"task":""}`, http.StatusBadRequest},
+		{"AddInvalidJSON", http.MethodPost, "/todos", `{`, http.StatusBadRequest},
+		{"List", http.MethodGet, "/todos", "", http.StatusOK},
+		{"BadSort", http.MethodGet, "/todos?sort=color", "", http.StatusBadRequest},
+		{"Update", http.MethodPatch, "/todos/1", `{"changes":"renamed !high"}`, http.StatusOK},
+		{"UpdateEmpty", http.MethodPatch, "/todos/1", `{"changes":""}`, http.StatusBadRequest},
+		{"Complete", http.MethodPost, "/todos/1/complete", "", http.StatusOK},
+		{"CompleteMissing", http.MethodPost, "/todos/9/complete", "", http.StatusNotFound},
+		{"Delete", http.MethodDelete, "/todos/1", "", http.StatusNoContent},
+		{"MethodNotAllowed", http.MethodPut, "/todos", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.exp {
+				t.Errorf("Expected status %d, got %d instead: %s", tc.exp, rec.Code, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Sink delivers reminders.
+type Sink interface {
+	Notify(r Reminder) error
+}
+
+// ParseSink returns the sink described by spec:
+//
+//	stdout               log reminders to standard output
+//	file:path            append them to a log file
+//	http://..., https:// POST them as JSON to a webhook URL
+//	exec:command         run a shell command with the reminder in its
+//	                     environment as TODO_ID, TODO_TASK, TODO_DUE and
+//	                     TODO_MESSAGE
+func ParseSink(spec string) (Sink, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch {
+	case spec == "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case kind == "file" && arg != "":
+		return NewFileSink(arg), nil
+	case kind == "http" || kind == "https":
+		return NewWebhookSink(spec), nil
+	case kind == "exec" && arg != "":
+		return NewCommandSink(arg), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidSink, spec)
+}
+
+// WriterSink logs reminders to a writer, one per line.
+type WriterSink struct {
+	log *log.Logger
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{log: log.New(w, "", log.LstdFlags)}
+}
+
+func (s *WriterSink) Notify(r Reminder) error {
+	return s.log.Output(2, "Reminder: "+r.Message)
+}
+
+// FileSink appends reminders to a log file, opening it for each one so
+// the file can be rotated while a watcher runs.
+type FileSink struct {
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Notify(r Reminder) error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if err := NewWriterSink(f).Notify(r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WebhookSink posts reminders as JSON to a URL.
+type WebhookSink struct {
+	url  string
+	http *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Notify(r Reminder) error {
+	js, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	resp, err := s.http.Post(s.url, "application/json", bytes.NewReader(js))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNotify, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s returned %s", ErrNotify, s.url, resp.Status)
+	}
+	return nil
+}
+
+// CommandSink runs a shell command for each reminder.
+type CommandSink struct {
+	command string
+}
+
+func NewCommandSink(command string) *CommandSink {
+	return &CommandSink{command: command}
+}
+
+func (s *CommandSink) Notify(r Reminder) error {
+	cmd := exec.Command("sh", "-c", s.command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", s.command)
+	}
+	cmd.Env = append(os.Environ(),
+		"TODO_ID="+r.ID,
+		"TODO_TASK="+r.Task,
+		"TODO_DUE="+r.Due.Format(dateLayout),
+		"TODO_MESSAGE="+r.Message,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s: %w: %s", ErrNotify, s.command, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
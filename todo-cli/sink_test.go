@@ -0,0 +1,109 @@
+package todo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func testReminder(t *testing.T) todo.Reminder {
+	t.Helper()
+	l := todo.List{}
+	l.Add("call the vendor due:2026-10-18")
+	return todo.Reminder{Entry: l.Entries(&todo.Query{})[0], Message: "Task 1 is due today"}
+}
+
+func TestParseSink(t *testing.T) {
+	testCases := []struct {
+		spec   string
+		expErr error
+	}{
+		{spec: "stdout"},
+		{spec: "file:reminders.log"},
+		{spec: "https://example.com/hook"},
+		{spec: "exec:notify-send todo"},
+		{spec: "file:", expErr: todo.ErrInvalidSink},
+		{spec: "email:me@example.com", expErr: todo.ErrInvalidSink},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			_, err := todo.ParseSink(tc.spec)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("Expected error %v, got %v instead", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.log")
+	s := todo.NewFileSink(path)
+	for range 2 {
+		if err := s.Notify(testReminder(t)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "Reminder: Task 1 is due today\n"); n != 2 {
+		t.Errorf("Expected 2 reminders logged, got %q", data)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer ts.Close()
+
+	if err := todo.NewWebhookSink(ts.URL).Notify(testReminder(t)); err != nil {
+		t.Fatal(err)
+	}
+	if got["task"] != "call the vendor" || got["message"] != "Task 1 is due today" || got["position"] != 1.0 {
+		t.Errorf("Unexpected webhook payload: %v", got)
+	}
+
+	if err := todo.NewWebhookSink(ts.URL + "/fail").Notify(testReminder(t)); !errors.Is(err, todo.ErrNotify) {
+		t.Errorf("Expected error %q, got %v instead", todo.ErrNotify, err)
+	}
+}
+
+func TestCommandSink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	s := todo.NewCommandSink(`printf '%s|%s|%s' "$TODO_TASK" "$TODO_DUE" "$TODO_MESSAGE" > ` + out)
+	if err := s.Notify(testReminder(t)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "call the vendor|2026-10-18|Task 1 is due today"
+	if string(data) != exp {
+		t.Errorf("Expected %q, got %q instead", exp, data)
+	}
+
+	if err := todo.NewCommandSink("exit 3").Notify(testReminder(t)); !errors.Is(err, todo.ErrNotify) {
+		t.Errorf("Expected error %q, got %v instead", todo.ErrNotify, err)
+	}
+}
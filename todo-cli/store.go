@@ -0,0 +1,113 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Store persists a List. Implementations must tolerate a missing backing
+// file by loading an empty list.
+type Store interface {
+	Load(l *List) error
+	Save(l *List) error
+	// Lock takes an exclusive lock on the store and returns a function
+	// releasing it.
+	Lock() (func() error, error)
+	// Path is the file backing the store.
+	Path() string
+}
+
+var storeKinds = map[string]func(path string) Store{
+	"json":    func(path string) Store { return NewJSONStore(path) },
+	"bolt":    func(path string) Store { return NewBoltStore(path) },
+	"todotxt": func(path string) Store { return NewTodoTxtStore(path) },
+	"jsonl":   func(path string) Store { return NewJSONLStore(path) },
+}
+
+// Open returns the store described by uri. A URI of the form
+// kind://path selects the backend explicitly, e.g. bolt:///home/me/todo.db
+// or todotxt://todo.txt. Otherwise names ending in .jsonl are JSON Lines
+// files and anything else is treated as a JSON file name.
+func Open(uri string) (Store, error) {
+	kind, path, ok := strings.Cut(uri, "://")
+	if !ok {
+		if strings.HasSuffix(uri, ".jsonl") {
+			return NewJSONLStore(uri), nil
+		}
+		return NewJSONStore(uri), nil
+	}
+	return OpenKind(kind, path)
+}
+
+// OpenKind returns a store of the given kind backed by path.
+func OpenKind(kind, path string) (Store, error) {
+	newStore, ok := storeKinds[strings.ToLower(kind)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStore, kind)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("%w: missing path for %s store", ErrUnknownStore, kind)
+	}
+	return newStore(path), nil
+}
+
+// storeAt returns a store of the same kind as s backed by path.
+func storeAt(s Store, path string) Store {
+	switch s.(type) {
+	case *BoltStore:
+		return NewBoltStore(path)
+	case *TodoTxtStore:
+		return NewTodoTxtStore(path)
+	case *JSONLStore:
+		return NewJSONLStore(path)
+	}
+	return NewJSONStore(path)
+}
+
+// Modify runs fn on the list held in s while holding the store's lock and
+// saves the result if fn succeeds.
+func Modify(s Store, fn func(l *List) error) error {
+	unlock, err := s.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	l := &List{}
+	if err := s.Load(l); err != nil {
+		return err
+	}
+	if err := fn(l); err != nil {
+		return err
+	}
+	return s.Save(l)
+}
+
+type JSONStore struct {
+	path string
+}
+
+func NewJSONStore(path string) *JSONStore {
+	return &JSONStore{path: path}
+}
+
+func (s *JSONStore) Load(l *List) error {
+	if err := l.Get(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *JSONStore) Save(l *List) error {
+	return l.Save(s.path)
+}
+
+func (s *JSONStore) Lock() (func() error, error) {
+	return Lock(s.path)
+}
+
+func (s *JSONStore) Path() string {
+	return s.path
+}
@@ -0,0 +1,160 @@
+package todo_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestModifyConcurrent(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), ".todo.json")
+
+	const workers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- todo.Modify(todo.NewJSONStore(fname), func(l *todo.List) error {
+				l.Add(fmt.Sprintf("task %d", i))
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := todo.List{}
+	if err := l.Get(fname); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != workers {
+		t.Errorf("Expected %d tasks, got %d instead", workers, len(l))
+	}
+
+	seen := map[string]bool{}
+	for _, it := range l {
+		if seen[it.Task] {
+			t.Errorf("Task %q saved more than once", it.Task)
+		}
+		seen[it.Task] = true
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(fname))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != ".todo.json" && e.Name() != ".todo.json.lock" {
+			t.Errorf("Unexpected file %q left behind", e.Name())
+		}
+	}
+}
+
+func TestModifyError(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), ".todo.json")
+	l := todo.List{}
+	l.Add("keep me")
+	if err := l.Save(fname); err != nil {
+		t.Fatal(err)
+	}
+
+	err := todo.Modify(todo.NewJSONStore(fname), func(l *todo.List) error {
+		l.Add("discard me")
+		return todo.ErrNotFound
+	})
+	if err != todo.ErrNotFound {
+		t.Fatalf("Expected error %q, got %q instead", todo.ErrNotFound, err)
+	}
+
+	l = todo.List{}
+	if err := l.Get(fname); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 1 {
+		t.Errorf("Expected failed modification not to be saved, got %d tasks", len(l))
+	}
+}
+
+func TestStores(t *testing.T) {
+	testCases := []struct {
+		name string
+		uri  string
+	}{
+		{"JSON", "todo.json"},
+		{"JSONScheme", "json://todo.json"},
+		{"Bolt", "bolt://todo.db"},
+		{"TodoTxt", "todotxt://todo.txt"},
+		{"JSONL", "todo.jsonl"},
+		{"JSONLScheme", "jsonl://todo.lines"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			uri := filepath.Join(dir, tc.uri)
+			if kind, path, ok := strings.Cut(tc.uri, "://"); ok {
+				uri = kind + "://" + filepath.Join(dir, path)
+			}
+			s, err := todo.Open(uri)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			l := todo.List{}
+			if err := s.Load(&l); err != nil {
+				t.Fatalf("Expected missing store to load empty, got error: %s", err)
+			}
+			if len(l) != 0 {
+				t.Fatalf("Expected empty list, got %d items", len(l))
+			}
+
+			err = todo.Modify(s, func(l *todo.List) error {
+				l.Add("first task +work !high due:2026-11-01")
+				l.Add("second task")
+				return l.Complete(2)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = todo.Modify(s, func(l *todo.List) error {
+				l.Add("third task")
+				return l.Delete(1)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.Load(&l); err != nil {
+				t.Fatal(err)
+			}
+			if len(l) != 2 {
+				t.Fatalf("Expected 2 items, got %d instead", len(l))
+			}
+			if l[0].Task != "second task" || !l[0].Done || l[1].Task != "third task" {
+				t.Errorf("Unexpected items loaded: %+v", l)
+			}
+			if l[0].ID == "" || l[0].ID == l[1].ID {
+				t.Errorf("Expected items to keep unique IDs, got %q and %q", l[0].ID, l[1].ID)
+			}
+		})
+	}
+}
+
+func TestOpenUnknownStore(t *testing.T) {
+	if _, err := todo.Open("redis://localhost"); !errors.Is(err, todo.ErrUnknownStore) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrUnknownStore, err)
+	}
+}
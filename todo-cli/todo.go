@@ -3,37 +3,133 @@ package todo
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"slices"
 	"time"
 )
 
 type item struct {
-	Task        string    `json:"task"`
-	Done        bool      `json:"done"`
-	CreatedAt   time.Time `json:"created_at"`
-	CompletedAt time.Time `json:"updated_at"`
+	ID           string    `json:"id"`
+	Task         string    `json:"task"`
+	Done         bool      `json:"done"`
+	CreatedAt    time.Time `json:"created_at"`
+	CompletedAt  time.Time `json:"updated_at"`
+	Priority     Priority  `json:"priority,omitzero"`
+	Due          time.Time `json:"due,omitzero"`
+	Tags         []string  `json:"tags,omitempty"`
+	Parent       string    `json:"parent,omitempty"`
+	BlockedBy    []string  `json:"blocked_by,omitempty"`
+	Recur        string    `json:"recur,omitempty"`
+	List         string    `json:"list,omitempty"`
+	SnoozedUntil time.Time `json:"snoozed_until,omitzero"`
+	// UID is the iCalendar UID of an item imported under a UID that is
+	// not one of ours, kept to export it under the same UID again.
+	UID string `json:"uid,omitempty"`
 }
 
 type List []item
 
 func (l *List) Add(task string) {
-	t := item{
-		Task:        task,
-		Done:        false,
-		CreatedAt:   time.Now(),
-		CompletedAt: time.Time{},
-	}
+	t := parseTask(task)
+	t.ID = l.newID()
+	t.CreatedAt = time.Now()
 	*l = append(*l, t)
 }
 
+// Import adds items to l. Items whose ID, or iCalendar UID, is already
+// in l replace the existing item rather than being added twice. Links
+// that would close a cycle with the items in l are dropped.
+func (l *List) Import(items List) {
+	ids := map[string]string{}
+	for _, t := range items {
+		for _, e := range *l {
+			if t.UID != "" && e.UID == t.UID {
+				ids[t.ID] = e.ID
+				break
+			}
+		}
+	}
+	resolve := func(id string) string {
+		if to, ok := ids[id]; ok && id != "" {
+			return to
+		}
+		return id
+	}
+	for _, t := range items {
+		t.ID, t.Parent = resolve(t.ID), resolve(t.Parent)
+		t.BlockedBy = slices.Clone(t.BlockedBy)
+		for j, id := range t.BlockedBy {
+			t.BlockedBy[j] = resolve(id)
+		}
+		if t.ID == "" {
+			t.ID = l.newID()
+		}
+		if i := l.indexOf(t.ID); i >= 0 {
+			(*l)[i] = t
+			continue
+		}
+		*l = append(*l, t)
+	}
+	l.breakCycles()
+}
+
+// Complete marks item i as done, adding the next occurrence of a
+// recurring item. Completing an item that is already done changes
+// nothing.
 func (l *List) Complete(i int) error {
+	return l.complete(i, false)
+}
+
+// ForceComplete completes item i even if it has open subtasks or is
+// blocked by open items.
+func (l *List) ForceComplete(i int) error {
+	return l.complete(i, true)
+}
+
+func (l *List) complete(i int, force bool) error {
 	ls := *l
 	if i <= 0 || i > len(ls) {
 		return fmt.Errorf("Item %d does not exist", i)
 	}
+	if ls[i-1].Done {
+		return nil
+	}
+	if !force {
+		if err := l.checkOpen(ls[i-1]); err != nil {
+			return err
+		}
+	}
 	ls[i-1].Done = true
 	ls[i-1].CompletedAt = time.Now()
+	if ls[i-1].Recur != "" {
+		l.recur(ls[i-1])
+	}
+	return nil
+}
+
+// Reopen marks the completed item i as not done again. The next
+// occurrence completing a recurring item added is deleted again, unless
+// it was completed or its text or recurrence changed since.
+func (l *List) Reopen(i int) error {
+	ls := *l
+	if i <= 0 || i > len(ls) {
+		return fmt.Errorf("Item %d does not exist", i)
+	}
+	t := ls[i-1]
+	if !t.Done {
+		return nil
+	}
+	ls[i-1].Done = false
+	ls[i-1].CompletedAt = time.Time{}
+	r, _, err := t.nextRule()
+	if t.Recur == "" || err != nil {
+		return nil
+	}
+	for j := len(ls) - 1; j >= 0; j-- {
+		n := ls[j]
+		if n.ID != t.ID && !n.Done && n.Task == t.Task && n.Recur == r.String() && n.CreatedAt.Equal(t.CompletedAt) {
+			return l.Delete(j + 1)
+		}
+	}
 	return nil
 }
 
@@ -42,7 +138,9 @@ func (l *List) Delete(i int) error {
 	if i <= 0 || i > len(ls) {
 		return fmt.Errorf("Item %d does not exist", i)
 	}
+	id := ls[i-1].ID
 	*l = slices.Delete(ls, i-1, i)
+	l.unlink(id)
 	return nil
 }
 
@@ -52,11 +150,11 @@ func (l *List) Save(filename string) error {
 		return err
 	}
 
-	return os.WriteFile(filename, js, 0644)
+	return writeFile(filename, js)
 }
 
 func (l *List) Get(filename string) error {
-	file, err := os.ReadFile(filename)
+	file, err := readFile(filename)
 	if err != nil {
 		return err
 	}
@@ -64,26 +162,21 @@ func (l *List) Get(filename string) error {
 		return nil
 	}
 
-	return json.Unmarshal(file, l)
+	if err := json.Unmarshal(file, l); err != nil {
+		return err
+	}
+	l.migrate()
+	return nil
 }
 
 func (l *List) String() string {
-	formatted := ""
-	for k, t := range *l {
-
-		prefix := " "
-		if t.Done {
-			prefix = "X "
-		}
-		formatted += fmt.Sprintf("%s%d: %s\n", prefix, k+1, t.Task)
-	}
-	return formatted
+	return l.View(&Query{sortBy: "position"})
 }
 
 func (l *List) PrintIncomplete() {
 	for i, item := range *l {
 		if !item.Done {
-			fmt.Printf("%d: %s\n", i+1, item.Task)
+			fmt.Printf("%d: %s\n", i+1, item.label())
 		}
 	}
 }
@@ -1,8 +1,12 @@
 package todo_test
 
 import (
+	"errors"
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/itsjayeshrathi/todo-cli"
 )
@@ -37,6 +41,52 @@ func TestComplete(t *testing.T) {
 	}
 }
 
+func TestReopen(t *testing.T) {
+	l := todo.List{}
+	l.Add("New Task")
+	l.Complete(1)
+
+	if err := l.Reopen(1); err != nil {
+		t.Fatal(err)
+	}
+	if l[0].Done || !l[0].CompletedAt.IsZero() {
+		t.Errorf("Reopened task should not be completed")
+	}
+	if err := l.Reopen(2); err == nil {
+		t.Error("Expected error reopening a missing item")
+	}
+}
+
+func TestReopenRecurring(t *testing.T) {
+	l := todo.List{}
+	l.Add("water plants rec:daily")
+	l.Add("call the vendor")
+	if err := l.Complete(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 3 {
+		t.Fatalf("Expected next occurrence to be added, got %d items", len(l))
+	}
+	if err := l.Reopen(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 2 || l[0].Done || l[1].Task != "call the vendor" {
+		t.Errorf("Expected next occurrence to be removed, got %v instead", l)
+	}
+
+	// An occurrence changed since it was spawned is kept.
+	if err := l.Complete(1); err != nil {
+		t.Fatal(err)
+	}
+	l[2].Task = "water all plants"
+	if err := l.Reopen(1); err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 3 {
+		t.Errorf("Expected changed occurrence to be kept, got %d items", len(l))
+	}
+}
+
 func TestDelete(t *testing.T) {
 	l := todo.List{}
 	tasks := []string{"task one", "task two", "task three"}
@@ -84,3 +134,137 @@ func TestSaveGet(t *testing.T) {
 		t.Errorf("Task %q should match %q", l1[0].Task, l2[0].Task)
 	}
 }
+
+func TestAddMetadata(t *testing.T) {
+	l := todo.List{}
+	l.Add("write report +work !high due:2026-11-01 +q4 due:someday")
+
+	if l[0].Task != "write report due:someday" {
+		t.Errorf("Expected %q, got %q instead", "write report due:someday", l[0].Task)
+	}
+	if l[0].Priority != todo.PriorityHigh {
+		t.Errorf("Expected priority %s, got %s instead", todo.PriorityHigh, l[0].Priority)
+	}
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	if !l[0].Due.Equal(due) {
+		t.Errorf("Expected due date %s, got %s instead", due, l[0].Due)
+	}
+	if !slices.Equal(l[0].Tags, []string{"work", "q4"}) {
+		t.Errorf("Expected tags %v, got %v instead", []string{"work", "q4"}, l[0].Tags)
+	}
+}
+
+func TestGetLegacyFile(t *testing.T) {
+	tf, err := os.CreateTemp("", "")
+	if err != nil {
+		t.Fatalf("Error creating temp file: %s", err)
+	}
+	defer os.Remove(tf.Name())
+
+	legacy := `[{"task":"old task","done":true,"created_at":"2025-05-10T10:00:00Z","updated_at":"2025-05-11T10:00:00Z"}]`
+	if _, err := tf.WriteString(legacy); err != nil {
+		t.Fatal(err)
+	}
+	tf.Close()
+
+	l := todo.List{}
+	if err := l.Get(tf.Name()); err != nil {
+		t.Fatalf("Error getting list from file: %s", err)
+	}
+	if l[0].Task != "old task" || !l[0].Done {
+		t.Errorf("Legacy item not loaded correctly: %+v", l[0])
+	}
+	if l[0].Priority != todo.PriorityNone || !l[0].Due.IsZero() || l[0].Tags != nil {
+		t.Errorf("Legacy item should have no metadata: %+v", l[0])
+	}
+}
+
+func TestIDs(t *testing.T) {
+	l := todo.List{}
+	tasks := []string{"task one", "task two", "task three"}
+	for _, v := range tasks {
+		l.Add(v)
+	}
+
+	id := l[2].ID
+	if id == "" || id == l[0].ID || id == l[1].ID {
+		t.Fatalf("Expected unique item IDs, got %q, %q, %q", l[0].ID, l[1].ID, id)
+	}
+
+	l.Delete(1)
+
+	i, err := l.Lookup(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l[i-1].Task != tasks[2] {
+		t.Errorf("Expected %q, got %q instead", tasks[2], l[i-1].Task)
+	}
+
+	i, err = l.Lookup(id[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l[i-1].ID != id {
+		t.Errorf("Expected prefix to resolve to %q, got %q instead", id, l[i-1].ID)
+	}
+
+	if i, err := l.Lookup("1"); err != nil || i != 1 {
+		t.Errorf("Expected index 1 to resolve to position 1, got %d (%v)", i, err)
+	}
+	if _, err := l.Lookup("5"); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNotFound, err)
+	}
+	if _, err := l.Lookup("zzzzzzzz1"); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrNotFound, err)
+	}
+}
+
+func TestGetMigratesIDs(t *testing.T) {
+	tf, err := os.CreateTemp("", "")
+	if err != nil {
+		t.Fatalf("Error creating temp file: %s", err)
+	}
+	defer os.Remove(tf.Name())
+
+	if _, err := tf.WriteString(`[{"task":"one"},{"task":"two"}]`); err != nil {
+		t.Fatal(err)
+	}
+	tf.Close()
+
+	l1 := todo.List{}
+	if err := l1.Get(tf.Name()); err != nil {
+		t.Fatalf("Error getting list from file: %s", err)
+	}
+	if l1[0].ID == "" || l1[1].ID == "" {
+		t.Fatalf("Expected IDs to be assigned, got %+v", l1)
+	}
+
+	l2 := todo.List{}
+	if err := l2.Get(tf.Name()); err != nil {
+		t.Fatalf("Error getting list from file: %s", err)
+	}
+	if l1[0].ID != l2[0].ID || l1[1].ID != l2[1].ID {
+		t.Errorf("Expected migrated IDs to be stable, got %+v and %+v", l1, l2)
+	}
+
+	// Reading must not write outside the lock; Modify saves the IDs.
+	data, err := os.ReadFile(tf.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[{"task":"one"},{"task":"two"}]` {
+		t.Errorf("Expected Get to leave the file alone, got %s instead", data)
+	}
+	s := todo.NewJSONStore(tf.Name())
+	if err := todo.Modify(s, func(*todo.List) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(tf.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), l1[0].ID) {
+		t.Errorf("Expected ID %q to be saved, got %s instead", l1[0].ID, data)
+	}
+}
@@ -0,0 +1,237 @@
+package todo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const snoozeLayout = "2006-01-02T15:04"
+
+// Priorities map onto the todo.txt (A)-(C) letters.
+var todoTxtPriorities = map[Priority]string{
+	PriorityHigh:   "A",
+	PriorityMedium: "B",
+	PriorityLow:    "C",
+}
+
+// ReadTodoTxt parses a list in the todo.txt format. Item IDs, parents,
+// blockers, snoozes and iCalendar UIDs are kept in id:, parent:, blocked:,
+// snooze: and uid: keys so they survive a round trip.
+func ReadTodoTxt(r io.Reader) (List, error) {
+	var l List
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		l = append(l, parseTodoTxt(line))
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	l.migrate()
+	l.breakCycles()
+	return l, nil
+}
+
+func parseTodoTxt(line string) item {
+	words := strings.Fields(line)
+	done := false
+	var completed, created time.Time
+	var pri Priority
+
+	if len(words) > 0 && words[0] == "x" {
+		done = true
+		words = words[1:]
+		if d, ok := todoTxtDate(words); ok {
+			completed = d
+			words = words[1:]
+		}
+	}
+	if len(words) > 0 && len(words[0]) == 3 && words[0][0] == '(' && words[0][2] == ')' {
+		if p, ok := todoTxtPriority(words[0][1:2]); ok {
+			pri = p
+			words = words[1:]
+		}
+	}
+	if d, ok := todoTxtDate(words); ok {
+		created = d
+		words = words[1:]
+	}
+
+	var id, parent, uid string
+	var blockedBy []string
+	var snoozed time.Time
+	rest := words[:0]
+	for _, w := range words {
+		switch {
+		case strings.HasPrefix(w, "id:") && len(w) > 3:
+			id = w[3:]
+		case strings.HasPrefix(w, "uid:") && len(w) > 4:
+			uid = w[4:]
+		case strings.HasPrefix(w, "parent:") && len(w) > 7:
+			parent = w[7:]
+		case strings.HasPrefix(w, "blocked:") && len(w) > 8:
+			blockedBy = append(blockedBy, strings.Split(w[8:], ",")...)
+		case strings.HasPrefix(w, "snooze:"):
+			d, err := time.ParseInLocation(snoozeLayout, w[7:], time.Local)
+			if err != nil {
+				rest = append(rest, w)
+				continue
+			}
+			snoozed = d
+		case strings.HasPrefix(w, "pri:") && len(w) == 5:
+			if p, ok := todoTxtPriority(w[4:]); ok {
+				pri = p
+				continue
+			}
+			rest = append(rest, w)
+		default:
+			rest = append(rest, w)
+		}
+	}
+
+	t := parseTask(strings.Join(rest, " "))
+	t.ID = id
+	t.Parent = parent
+	t.BlockedBy = blockedBy
+	t.SnoozedUntil = snoozed
+	t.UID = uid
+	t.Done = done
+	t.CompletedAt = completed
+	t.CreatedAt = created
+	if pri != PriorityNone {
+		t.Priority = pri
+	}
+	return t
+}
+
+func todoTxtDate(words []string) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	d, err := time.ParseInLocation(dateLayout, words[0], time.Local)
+	return d, err == nil
+}
+
+func todoTxtPriority(letter string) (Priority, bool) {
+	for p, l := range todoTxtPriorities {
+		if l == letter {
+			return p, true
+		}
+	}
+	return PriorityNone, false
+}
+
+// WriteTodoTxt writes l in the todo.txt format, one item per line.
+func (l *List) WriteTodoTxt(w io.Writer) error {
+	for _, t := range *l {
+		if _, err := fmt.Fprintln(w, t.todoTxt()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t item) todoTxt() string {
+	var parts []string
+	if t.Done {
+		parts = append(parts, "x")
+		completed := t.CompletedAt
+		if completed.IsZero() {
+			completed = t.CreatedAt
+		}
+		if !completed.IsZero() {
+			parts = append(parts, completed.Format(dateLayout))
+		}
+	} else if letter, ok := todoTxtPriorities[t.Priority]; ok {
+		parts = append(parts, "("+letter+")")
+	}
+	if !t.CreatedAt.IsZero() {
+		parts = append(parts, t.CreatedAt.Format(dateLayout))
+	}
+	if t.Task != "" {
+		parts = append(parts, t.Task)
+	}
+	for _, tag := range t.Tags {
+		parts = append(parts, tagToken(tag))
+	}
+	if !t.Due.IsZero() {
+		parts = append(parts, "due:"+t.Due.Format(dateLayout))
+	}
+	if t.Recur != "" {
+		parts = append(parts, "rec:"+t.Recur)
+	}
+	if t.List != "" {
+		parts = append(parts, "list:"+t.List)
+	}
+	if letter, ok := todoTxtPriorities[t.Priority]; ok && t.Done {
+		parts = append(parts, "pri:"+letter)
+	}
+	if t.Parent != "" {
+		parts = append(parts, "parent:"+t.Parent)
+	}
+	if len(t.BlockedBy) > 0 {
+		parts = append(parts, "blocked:"+strings.Join(t.BlockedBy, ","))
+	}
+	if !t.SnoozedUntil.IsZero() {
+		parts = append(parts, "snooze:"+t.SnoozedUntil.In(time.Local).Format(snoozeLayout))
+	}
+	if t.UID != "" && !strings.ContainsAny(t.UID, " \t") {
+		parts = append(parts, "uid:"+t.UID)
+	}
+	if t.ID != "" {
+		parts = append(parts, "id:"+t.ID)
+	}
+	return strings.Join(parts, " ")
+}
+
+// TodoTxtStore keeps the list in a plain todo.txt file.
+type TodoTxtStore struct {
+	path string
+}
+
+func NewTodoTxtStore(path string) *TodoTxtStore {
+	return &TodoTxtStore{path: path}
+}
+
+func (s *TodoTxtStore) Load(l *List) error {
+	data, err := readFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		*l = (*l)[:0]
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	items, err := ReadTodoTxt(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+func (s *TodoTxtStore) Save(l *List) error {
+	var buf bytes.Buffer
+	if err := l.WriteTodoTxt(&buf); err != nil {
+		return err
+	}
+	return writeFile(s.path, buf.Bytes())
+}
+
+func (s *TodoTxtStore) Lock() (func() error, error) {
+	return Lock(s.path)
+}
+
+func (s *TodoTxtStore) Path() string {
+	return s.path
+}
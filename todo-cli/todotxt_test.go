@@ -0,0 +1,134 @@
+package todo_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itsjayeshrathi/todo-cli"
+)
+
+func TestReadTodoTxt(t *testing.T) {
+	input := `(A) 2026-10-01 call the vendor +work due:2026-11-01
+x 2026-10-05 2026-10-02 water plants +home pri:C
+
+2026-10-03 plain task
+`
+	l, err := todo.ReadTodoTxt(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l) != 3 {
+		t.Fatalf("Expected 3 items, got %d instead", len(l))
+	}
+
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local) }
+
+	if l[0].Task != "call the vendor" || l[0].Priority != todo.PriorityHigh || l[0].Done {
+		t.Errorf("Unexpected first item: %+v", l[0])
+	}
+	if !l[0].CreatedAt.Equal(day(1)) || !l[0].Due.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Unexpected dates on first item: %+v", l[0])
+	}
+	if !l[1].Done || !l[1].CompletedAt.Equal(day(5)) || !l[1].CreatedAt.Equal(day(2)) {
+		t.Errorf("Unexpected completion on second item: %+v", l[1])
+	}
+	if l[1].Priority != todo.PriorityLow || l[1].Tags[0] != "home" {
+		t.Errorf("Unexpected metadata on second item: %+v", l[1])
+	}
+	if l[2].Task != "plain task" || l[2].ID == "" {
+		t.Errorf("Unexpected third item: %+v", l[2])
+	}
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	input := "(A) 2026-10-01 call the vendor +work @phone due:2026-11-01 snooze:2026-10-30T09:30 uid:42 id:aaaaaaaa\n" +
+		"x 2026-10-05 2026-10-02 water plants +home pri:C id:bbbbbbbb\n"
+
+	l, err := todo.ReadTodoTxt(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := l.WriteTodoTxt(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != input {
+		t.Errorf("Expected %q, got %q instead", input, out.String())
+	}
+}
+
+func TestTodoTxtLinksRoundTrip(t *testing.T) {
+	s := todo.NewTodoTxtStore(filepath.Join(t.TempDir(), "todo.txt"))
+	l := &todo.List{}
+	for _, task := range []string{"release", "write notes", "tag build", "ship"} {
+		l.Add(task)
+	}
+	if err := l.SetParent(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, on := range []int{2, 3} {
+		if err := l.AddDependency(4, on); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Save(l); err != nil {
+		t.Fatal(err)
+	}
+
+	got := loadList(t, s)
+	if got[1].Parent != got[0].ID {
+		t.Errorf("Expected parent %q, got %q instead", got[0].ID, got[1].Parent)
+	}
+	if len(got[3].BlockedBy) != 2 || got[3].BlockedBy[0] != got[1].ID || got[3].BlockedBy[1] != got[2].ID {
+		t.Errorf("Expected blockers %q and %q, got %q instead", got[1].ID, got[2].ID, got[3].BlockedBy)
+	}
+	if err := got.Complete(1); !errors.Is(err, todo.ErrOpenSubtasks) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrOpenSubtasks, err)
+	}
+	if err := got.Complete(4); !errors.Is(err, todo.ErrBlocked) {
+		t.Errorf("Expected error %q, got %q instead", todo.ErrBlocked, err)
+	}
+}
+
+func TestReadTodoTxtDropsCycles(t *testing.T) {
+	input := "a id:aaaaaaaa parent:bbbbbbbb blocked:bbbbbbbb\n" +
+		"b id:bbbbbbbb parent:aaaaaaaa blocked:aaaaaaaa\n"
+	l, err := todo.ReadTodoTxt(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l[0].Parent != "bbbbbbbb" || l[1].Parent != "" {
+		t.Errorf("Expected only the first parent link kept, got %q and %q", l[0].Parent, l[1].Parent)
+	}
+	if len(l[0].BlockedBy) != 1 || len(l[1].BlockedBy) != 0 {
+		t.Errorf("Expected only the first blocker kept, got %q and %q", l[0].BlockedBy, l[1].BlockedBy)
+	}
+}
+
+func TestImport(t *testing.T) {
+	l := todo.List{}
+	l.Add("call the vendor")
+	l.Add("water plants")
+
+	input := "x 2026-10-05 2026-10-02 water plants +home id:" + l[1].ID + "\n" +
+		"2026-10-03 new task @office\n"
+	items, err := todo.ReadTodoTxt(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Import(items)
+
+	if len(l) != 3 {
+		t.Fatalf("Expected 3 items, got %d instead", len(l))
+	}
+	if !l[1].Done || !l[1].CompletedAt.Equal(time.Date(2026, 10, 5, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected imported item to replace existing one, got %+v", l[1])
+	}
+	if l[2].Task != "new task" || l[2].Tags[0] != "@office" {
+		t.Errorf("Unexpected imported item: %+v", l[2])
+	}
+}